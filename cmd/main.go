@@ -9,16 +9,32 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/NKV510/question-answer-api/internal/audit"
+	"github.com/NKV510/question-answer-api/internal/auth"
 	"github.com/NKV510/question-answer-api/internal/config"
 	"github.com/NKV510/question-answer-api/internal/database"
 	"github.com/NKV510/question-answer-api/internal/handlers"
+	"github.com/NKV510/question-answer-api/internal/health"
+	"github.com/NKV510/question-answer-api/internal/idempotency"
+	"github.com/NKV510/question-answer-api/internal/logging"
+	"github.com/NKV510/question-answer-api/internal/metrics"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/openapi"
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/NKV510/question-answer-api/internal/ratelimit"
 	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"github.com/NKV510/question-answer-api/internal/tracing"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	setupLogging()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	slog.Info("Starting question-answer API server")
 
 	cfg, err := config.LoadConfig()
@@ -33,23 +49,80 @@ func main() {
 		"db_name", cfg.DBName,
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter, cfg.TracesFile)
+	if err != nil {
+		slog.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
 	if err := database.ConnectDataBase(cfg); err != nil {
 		slog.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
 	}
 
+	if cfg.AutoMigrate {
+		if err := applyMigrations(context.Background()); err != nil {
+			slog.Error("Failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	schemaReady := checkSchema(context.Background(), cfg.SchemaCheck)
+
+	if err := instrumentDB(); err != nil {
+		slog.Error("Failed to instrument database", "error", err)
+		os.Exit(1)
+	}
+
+	authenticator, err := auth.NewAuthenticator(cfg)
+	if err != nil {
+		slog.Error("Failed to configure authentication", "error", err)
+		os.Exit(1)
+	}
+
 	repo := repository.NewRepository(database.GetDB())
 
 	handler := handlers.NewHandler(repo)
 
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go runTrashPurge(purgeCtx, repo, cfg.TrashRetention, cfg.PurgeInterval)
+
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Error("Failed to set trusted proxies", "error", err)
+		os.Exit(1)
+	}
+
+	router.Use(requestid.Middleware())
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		problem.Abort(c, http.StatusInternalServerError, "")
+	}))
 
-	router.Use(gin.Recovery())
+	checker := health.New(database.GetDB(), cfg.ReadinessTimeout)
+	if schemaReady != nil {
+		checker.AddCheck("schema", schemaReady)
+	}
+
+	// Registered before the rest of the chain, so probes and scrapes never
+	// hit authentication or rate limits.
+	setupOpsRoutes(router, checker)
+
+	router.Use(tracing.Middleware())
+	router.Use(metrics.Middleware())
 	router.Use(loggingMiddleware())
+	// Requests with a bad token are counted against the client IP before
+	// they are rejected, so tokens can't be guessed without limit.
+	router.Use(authenticator.Identify())
+	router.Use(newRateLimiter(cfg).Middleware())
+	router.Use(auth.RejectInvalid())
+	router.Use(audit.Middleware())
+	router.Use(handler.EnsureUser())
+	router.Use(idempotency.New(idempotency.NewPostgresStore(database.GetDB()), cfg.IdempotencyTTL).Handler())
 
 	setupRoutes(router, handler)
 
@@ -73,7 +146,12 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	slog.Info("Shutting down server...")
+	slog.Info("Shutting down server...", "drain_delay", cfg.ShutdownDrainDelay.String())
+	checker.Drain()
+	stopPurge()
+
+	// Keep serving while load balancers notice /readyz failing.
+	time.Sleep(cfg.ShutdownDrainDelay)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -81,21 +159,81 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
 
 	slog.Info("Server exited")
 }
 
+// instrumentDB installs the GORM metrics and tracing plugins and exports the
+// connection pool stats.
+func instrumentDB() error {
+	db := database.GetDB()
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return err
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return metrics.RegisterDBStats(sqlDB)
+}
+
+func newRateLimiter(cfg *config.Config) *ratelimit.Limiter {
+	read := ratelimit.Policy{PerMinute: cfg.RateLimitRead, Burst: cfg.RateLimitReadBurst}
+	write := ratelimit.Policy{PerMinute: cfg.RateLimitWrite, Burst: cfg.RateLimitWriteBurst}
+
+	var store ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == "postgres" {
+		store = ratelimit.NewPostgresStore(database.GetDB(), read, write)
+	}
+
+	slog.Info("Rate limiting configured",
+		"store", cfg.RateLimitStore,
+		"read_per_minute", cfg.RateLimitRead,
+		"write_per_minute", cfg.RateLimitWrite,
+	)
+
+	return ratelimit.NewLimiter(store, read, write)
+}
+
+// runTrashPurge periodically removes trashed content older than retention
+// until ctx is cancelled.
+func runTrashPurge(ctx context.Context, repo *repository.Repository, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			slog.Error("Trash purge failed", "error", err)
+		} else if purged > 0 {
+			slog.Info("Purged deleted content", "rows", purged, "retention", retention.String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func setupLogging() {
 	if os.Getenv("ENV") == "production" {
 		handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
 		})
-		slog.SetDefault(slog.New(handler))
+		slog.SetDefault(slog.New(logging.NewContextHandler(handler)))
 	} else {
 		handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 			Level: slog.LevelDebug,
 		})
-		slog.SetDefault(slog.New(handler))
+		slog.SetDefault(slog.New(logging.NewContextHandler(handler)))
 	}
 }
 
@@ -115,45 +253,85 @@ func loggingMiddleware() gin.HandlerFunc {
 			"client_ip", c.ClientIP(),
 		)
 
+		ctx := c.Request.Context()
 		if c.Writer.Status() >= 500 {
-			logger.Error("HTTP request error")
+			logger.ErrorContext(ctx, "HTTP request error")
 		} else if c.Writer.Status() >= 400 {
-			logger.Warn("HTTP request client error")
+			logger.WarnContext(ctx, "HTTP request client error")
 		} else {
-			logger.Info("HTTP request")
+			logger.InfoContext(ctx, "HTTP request")
 		}
 	}
 }
 
+// setupOpsRoutes registers the health probes and the metrics endpoint. Only
+// middleware added to router before this call applies to them.
+func setupOpsRoutes(router *gin.Engine, checker *health.Checker) {
+	router.GET(health.LivePath, checker.Live)
+	router.GET(health.ReadyPath, checker.Ready)
+	router.GET(metrics.Path, metrics.Handler())
+}
+
 func setupRoutes(router *gin.Engine, handler *handlers.Handler) {
+	requireAuth := auth.RequireAuth()
+	requireModerator := handler.RequireRole(models.RoleModerator)
+
 	questions := router.Group("/questions")
 	{
 		questions.GET("/", handler.GetQuestions)
-		questions.POST("/", handler.CreateQuestion)
+		questions.POST("/", requireAuth, handler.CreateQuestion)
 		questions.GET("/:id", handler.GetQuestion)
-		questions.DELETE("/:id", handler.DeleteQuestion)
+		questions.PATCH("/:id", requireAuth, handler.UpdateQuestion)
+		questions.DELETE("/:id", requireAuth, handler.DeleteQuestion)
+		questions.POST("/:id/restore", requireAuth, handler.RestoreQuestion)
+		questions.GET("/:id/revisions", handler.GetQuestionRevisions)
+		questions.GET("/:id/revisions/diff", handler.DiffQuestionRevisions)
+		questions.POST("/:id/accept/:answer_id", requireAuth, handler.AcceptAnswer)
+		questions.DELETE("/:id/accept/:answer_id", requireAuth, handler.UnacceptAnswer)
+		questions.POST("/:id/status", requireAuth, requireModerator, handler.ChangeQuestionStatus)
+		questions.GET("/:id/status/history", handler.GetQuestionStatusHistory)
+		questions.GET("/:id/comments", handler.GetQuestionComments)
+		questions.POST("/:id/comments", requireAuth, handler.CreateQuestionComment)
 	}
 
 	answers := router.Group("/answers")
 	{
 		answers.GET("/:id", handler.GetAnswer)
-		answers.DELETE("/:id", handler.DeleteAnswer)
+		answers.PATCH("/:id", requireAuth, handler.UpdateAnswer)
+		answers.DELETE("/:id", requireAuth, handler.DeleteAnswer)
+		answers.GET("/:id/revisions", handler.GetAnswerRevisions)
+		answers.GET("/:id/revisions/diff", handler.DiffAnswerRevisions)
+		answers.POST("/:id/vote", requireAuth, handler.VoteAnswer)
+		answers.DELETE("/:id/vote", requireAuth, handler.RetractVote)
+		answers.GET("/:id/comments", handler.GetAnswerComments)
+		answers.POST("/:id/comments", requireAuth, handler.CreateAnswerComment)
+	}
+
+	router.POST("/questions/:id/answers", requireAuth, handler.CreateAnswer)
+	router.DELETE("/comments/:id", requireAuth, handler.DeleteComment)
+
+	users := router.Group("/users")
+	{
+		users.GET("/:id", handler.GetUser)
+		users.GET("/:id/answers", handler.GetUserAnswers)
+		users.GET("/:id/questions", handler.GetUserQuestions)
 	}
 
-	router.POST("/questions/:id/answers", handler.CreateAnswer)
+	router.GET("/search", handler.Search)
+	router.GET("/tags", handler.GetTags)
+	router.GET("/trash", requireAuth, handler.GetTrash)
 
-	// router.GET("/health", func(c *gin.Context) {
-	// 	c.JSON(http.StatusOK, gin.H{
-	// 		"status":    "ok",
-	// 		"timestamp": time.Now().Unix(),
-	// 		"service":   "question-answer-api",
-	// 	})
-	// })
+	router.GET(openapi.SpecPath, openapi.SpecHandler)
+	router.GET(openapi.DocsPath, openapi.DocsHandler)
+
+	admin := router.Group("/admin", requireAuth, handler.RequireRole(models.RoleAdmin))
+	{
+		admin.PUT("/users/:id/role", handler.GrantRole)
+		admin.DELETE("/users/:id/role", handler.RevokeRole)
+		admin.GET("/audit", handler.GetAuditEvents)
+	}
 
 	router.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Route not found",
-			"path":  c.Request.URL.Path,
-		})
+		problem.Abort(c, http.StatusNotFound, "Route not found")
 	})
 }
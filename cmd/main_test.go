@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/handlers"
+	"github.com/NKV510/question-answer-api/internal/health"
+	"github.com/NKV510/question-answer-api/internal/metrics"
+	"github.com/NKV510/question-answer-api/internal/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOpenAPICoversRoutes fails when a route is registered without a spec
+// entry, or the spec documents a route that no longer exists.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	setupOpsRoutes(router, health.New(nil, time.Second))
+	setupRoutes(router, handlers.NewHandler(nil))
+
+	doc := openapi.Build()
+
+	registered := make(map[string]bool)
+	for _, r := range router.Routes() {
+		registered[r.Method+" "+r.Path] = true
+		assert.True(t, doc.HasOperation(r.Method, r.Path), "route %s %s is missing from the OpenAPI spec", r.Method, r.Path)
+	}
+	for _, op := range doc.Operations() {
+		assert.True(t, registered[op], "OpenAPI spec documents %s, which is not registered", op)
+	}
+}
+
+// TestOpsRoutesBypassMiddleware checks that probes and scrapes are not
+// subject to middleware added after them, such as rate limiting.
+func TestOpsRoutesBypassMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	setupOpsRoutes(router, health.New(nil, time.Second))
+	router.Use(func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	})
+	setupRoutes(router, handlers.NewHandler(nil))
+
+	for path, want := range map[string]int{
+		health.LivePath: http.StatusOK,
+		metrics.Path:    http.StatusOK,
+		"/tags":         http.StatusTooManyRequests,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code, path)
+	}
+}
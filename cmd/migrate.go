@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/config"
+	"github.com/NKV510/question-answer-api/internal/database"
+	"github.com/NKV510/question-answer-api/internal/migrate"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/schemacheck"
+	"github.com/pressly/goose/v3"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up                     apply all pending migrations
+  down                   roll back the most recent migration
+  redo                   roll back the most recent migration and apply it again
+  status                 list migrations and when they were applied
+  check                  compare the database schema with the GORM models
+  create [-dir DIR] NAME write an empty migration to DIR (default migrations)
+`
+
+// runMigrate implements the migrate subcommand and returns the exit code.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if args[0] == "create" {
+		return createMigration(args[1:])
+	}
+
+	switch args[0] {
+	case "up", "down", "redo", "status", "check":
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		slog.Error("Failed to load config", "error", err)
+		return 1
+	}
+	if err := database.ConnectDataBase(cfg); err != nil {
+		return 1
+	}
+
+	ctx := context.Background()
+	if args[0] == "check" {
+		return checkSchemaCommand(ctx)
+	}
+
+	runner, err := newMigrationRunner()
+	if err != nil {
+		slog.Error("Failed to load migrations", "error", err)
+		return 1
+	}
+	defer runner.Close()
+
+	switch args[0] {
+	case "up":
+		results, err := runner.Up(ctx)
+		printResults(os.Stdout, results...)
+		if err == nil && len(results) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return exitCode(err)
+	case "down":
+		result, err := runner.Down(ctx)
+		printResults(os.Stdout, result)
+		return exitCode(err)
+	case "redo":
+		results, err := runner.Redo(ctx)
+		printResults(os.Stdout, results...)
+		return exitCode(err)
+	default:
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return exitCode(err)
+		}
+		printStatus(os.Stdout, statuses)
+		return 0
+	}
+}
+
+func createMigration(args []string) int {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	dir := flags.String("dir", "migrations", "directory to write the migration to")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	path, err := migrate.Create(*dir, flags.Arg(0), time.Now())
+	if err != nil {
+		slog.Error("Failed to create migration", "error", err)
+		return 1
+	}
+	fmt.Println("created", path)
+	return 0
+}
+
+// applyMigrations runs pending migrations at startup when AUTO_MIGRATE is
+// set. Concurrent instances wait for each other on the advisory lock.
+func applyMigrations(ctx context.Context) error {
+	runner, err := newMigrationRunner()
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	results, err := runner.Up(ctx)
+	for _, r := range results {
+		if r != nil && r.Error == nil {
+			slog.Info("Applied migration", "migration", filepath.Base(r.Source.Path), "duration", r.Duration.String())
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := runner.Version(ctx)
+	if err != nil {
+		return err
+	}
+	slog.Info("Database schema is up to date", "version", version, "applied", len(results))
+	return nil
+}
+
+func checkSchemaCommand(ctx context.Context) int {
+	issues, err := schemacheck.Check(ctx, database.GetDB(), models.All()...)
+	if err != nil {
+		slog.Error("Schema check failed", "error", err)
+		return 1
+	}
+	if len(issues) == 0 {
+		fmt.Println("schema matches models")
+		return 0
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	return 1
+}
+
+// checkSchema compares the models with the database at startup and logs any
+// drift. In strict mode it returns a readiness check that fails while drift
+// was found or the check could not run; otherwise it returns nil.
+func checkSchema(ctx context.Context, mode string) func(context.Context) error {
+	if mode == "off" {
+		return nil
+	}
+
+	issues, err := schemacheck.Check(ctx, database.GetDB(), models.All()...)
+	switch {
+	case err != nil:
+		slog.Error("Schema check failed", "error", err)
+		err = fmt.Errorf("schema check failed: %w", err)
+	case len(issues) > 0:
+		for _, issue := range issues {
+			slog.Warn("Schema drift", "table", issue.Table, "kind", issue.Kind, "object", issue.Object,
+				"expected", issue.Expected, "actual", issue.Actual)
+		}
+		err = fmt.Errorf("%d differences between models and database schema", len(issues))
+	default:
+		slog.Info("Database schema matches models")
+	}
+
+	if mode != "strict" {
+		return nil
+	}
+	return func(context.Context) error { return err }
+}
+
+func newMigrationRunner() (*migrate.Runner, error) {
+	sqlDB, err := database.GetDB().DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB)
+}
+
+func printResults(w io.Writer, results ...*goose.MigrationResult) {
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		state := "OK"
+		if r.Error != nil {
+			state = "FAIL"
+		}
+		fmt.Fprintf(w, "%-4s %-4s %s (%s)\n", state, r.Direction, filepath.Base(r.Source.Path), r.Duration.Round(time.Millisecond))
+	}
+}
+
+func printStatus(w io.Writer, statuses []*goose.MigrationStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "APPLIED AT\tMIGRATION")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.State == goose.StateApplied {
+			applied = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", applied, filepath.Base(s.Source.Path))
+	}
+	tw.Flush()
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, migrate.ErrNoMigrations):
+		fmt.Println(err)
+		return 0
+	default:
+		slog.Error("Migration failed", "error", err)
+		return 1
+	}
+}
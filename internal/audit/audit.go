@@ -0,0 +1,48 @@
+// Package audit carries the request metadata recorded with every audit event
+// from the HTTP layer down to the repository transaction that writes it.
+package audit
+
+import (
+	"context"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+)
+
+// Meta describes who made a change and from where. Actor is empty for
+// changes made by the service itself, such as the trash purge.
+type Meta struct {
+	Actor     string
+	RequestID string
+	ClientIP  string
+}
+
+type contextKey struct{}
+
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, contextKey{}, meta)
+}
+
+// FromContext returns the metadata stored by WithMeta, or a zero Meta.
+func FromContext(ctx context.Context) Meta {
+	meta, _ := ctx.Value(contextKey{}).(Meta)
+	return meta
+}
+
+// Middleware stores the caller, request ID and client IP in the request
+// context. It must run after authentication and requestid.Middleware. The
+// client IP comes from X-Forwarded-For only when the peer is one of the
+// engine's trusted proxies, so callers can't choose what gets recorded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, _ := auth.UserID(c)
+		meta := Meta{
+			Actor:     actor,
+			RequestID: requestid.FromContext(c.Request.Context()),
+			ClientIP:  c.ClientIP(),
+		}
+		c.Request = c.Request.WithContext(WithMeta(c.Request.Context(), meta))
+		c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext_Empty(t *testing.T) {
+	assert.Equal(t, Meta{}, FromContext(context.Background()))
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	var got Meta
+	router.Use(requestid.Middleware())
+	router.Use(func(c *gin.Context) {
+		auth.SetUserID(c, "user1")
+		c.Next()
+	})
+	router.Use(Middleware())
+	router.GET("/", func(c *gin.Context) {
+		got = FromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set(requestid.Header, "req-42")
+	req.RemoteAddr = "203.0.113.7:5555"
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, Meta{Actor: "user1", RequestID: "req-42", ClientIP: "203.0.113.7"}, got)
+}
+
+func TestMiddleware_ClientIPFromTrustedProxyOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(trusted []string) string {
+		router := gin.New()
+		require.NoError(t, router.SetTrustedProxies(trusted))
+
+		var got Meta
+		router.Use(Middleware())
+		router.GET("/", func(c *gin.Context) {
+			got = FromContext(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "203.0.113.7:5555"
+		req.Header.Set("X-Forwarded-For", "198.51.100.9")
+		router.ServeHTTP(w, req)
+		return got.ClientIP
+	}
+
+	assert.Equal(t, "203.0.113.7", clientIP(nil))
+	assert.Equal(t, "198.51.100.9", clientIP([]string{"203.0.113.0/24"}))
+}
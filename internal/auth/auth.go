@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/NKV510/question-answer-api/internal/config"
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// identityKey is the gin context key holding the authenticated Identity.
+const identityKey = "auth.identity"
+
+// failureKey is the gin context key holding the authFailure of a request
+// whose bearer token was rejected.
+const failureKey = "auth.failure"
+
+type authFailure struct {
+	detail       string
+	invalidToken bool
+}
+
+// Identity is the caller described by a validated token.
+type Identity struct {
+	Subject  string
+	Name     string
+	Username string
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+var ErrNoKeys = errors.New("no JWT verification keys configured")
+
+// Authenticator validates HS256 and RS256 bearer tokens.
+type Authenticator struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	jwks      map[string]*rsa.PublicKey
+	parser    *jwt.Parser
+}
+
+func NewAuthenticator(cfg *config.Config) (*Authenticator, error) {
+	a := &Authenticator{}
+
+	if cfg.JWTSecret != "" {
+		a.secret = []byte(cfg.JWTSecret)
+	}
+
+	if cfg.JWTPublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read JWT public key: %w", err)
+		}
+		a.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse JWT public key: %w", err)
+		}
+	}
+
+	if cfg.JWTJWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWTJWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+	}
+
+	var methods []string
+	if a.secret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if a.publicKey != nil || len(a.jwks) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWTAudience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+func (a *Authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.secret, nil
+	case jwt.SigningMethodRS256.Alg():
+		if kid, ok := token.Header["kid"].(string); ok && len(a.jwks) > 0 {
+			if key, ok := a.jwks[kid]; ok {
+				return key, nil
+			}
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if a.publicKey != nil {
+			return a.publicKey, nil
+		}
+		if len(a.jwks) == 1 {
+			for _, key := range a.jwks {
+				return key, nil
+			}
+		}
+		return nil, errors.New("token has no key id")
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// Authenticate parses the bearer token and returns the caller it describes.
+func (a *Authenticator) Authenticate(tokenString string) (Identity, error) {
+	var c claims
+	if _, err := a.parser.ParseWithClaims(tokenString, &c, a.keyFunc); err != nil {
+		return Identity{}, err
+	}
+	if c.Subject == "" {
+		return Identity{}, errors.New("token has no subject")
+	}
+	return Identity{
+		Subject:  c.Subject,
+		Name:     c.Name,
+		Username: c.PreferredUsername,
+	}, nil
+}
+
+// Middleware authenticates requests that carry a bearer token and rejects
+// invalid ones with 401. Requests without one pass through anonymously;
+// routes that need a user are guarded by RequireAuth. It is Identify and
+// RejectInvalid in one step.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.identify(c)
+		if rejectFailure(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// Identify is Middleware without the rejection: a request with an invalid
+// token continues anonymously until RejectInvalid. Handlers in between, such
+// as the rate limiter, see it as an anonymous client.
+func (a *Authenticator) Identify() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a.identify(c)
+		c.Next()
+	}
+}
+
+// RejectInvalid responds 401 to requests whose token Identify rejected.
+func RejectInvalid() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rejectFailure(c) {
+			return
+		}
+		c.Next()
+	}
+}
+
+func (a *Authenticator) identify(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return
+	}
+
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		c.Set(failureKey, authFailure{detail: "Invalid authorization header"})
+		return
+	}
+
+	identity, err := a.Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "Invalid bearer token", "error", err)
+		c.Set(failureKey, authFailure{detail: "Invalid token", invalidToken: true})
+		return
+	}
+
+	SetIdentity(c, identity)
+}
+
+func rejectFailure(c *gin.Context) bool {
+	value, ok := c.Get(failureKey)
+	if !ok {
+		return false
+	}
+	failure := value.(authFailure)
+	if failure.invalidToken {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+	problem.Abort(c, http.StatusUnauthorized, failure.detail)
+	return true
+}
+
+// RequireAuth rejects requests that were not authenticated by Middleware.
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := UserID(c); !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			problem.Abort(c, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		c.Next()
+	}
+}
+
+func SetIdentity(c *gin.Context, identity Identity) {
+	c.Set(identityKey, identity)
+}
+
+func SetUserID(c *gin.Context, userID string) {
+	SetIdentity(c, Identity{Subject: userID})
+}
+
+// CurrentIdentity returns the authenticated caller of the request.
+func CurrentIdentity(c *gin.Context) (Identity, bool) {
+	identity, ok := c.Get(identityKey)
+	if !ok {
+		return Identity{}, false
+	}
+	id, ok := identity.(Identity)
+	return id, ok && id.Subject != ""
+}
+
+// UserID returns the authenticated subject of the request.
+func UserID(c *gin.Context) (string, bool) {
+	identity, ok := CurrentIdentity(c)
+	return identity.Subject, ok
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+func signHS256(t *testing.T, claims jwt.RegisteredClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func setupRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	authenticator, err := NewAuthenticator(&config.Config{JWTSecret: testSecret})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(authenticator.Middleware())
+	router.POST("/write", RequireAuth(), func(c *gin.Context) {
+		userID, _ := UserID(c)
+		c.String(http.StatusOK, userID)
+	})
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	router := setupRouter(t)
+
+	valid := signHS256(t, jwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	expired := signHS256(t, jwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"valid token", "Bearer " + valid, http.StatusOK},
+		{"no token", "", http.StatusUnauthorized},
+		{"expired token", "Bearer " + expired, http.StatusUnauthorized},
+		{"wrong scheme", "Basic " + valid, http.StatusUnauthorized},
+		{"garbage", "Bearer not-a-jwt", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/write", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, "user-1", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIdentify_RejectsLater(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authenticator, err := NewAuthenticator(&config.Config{JWTSecret: testSecret})
+	require.NoError(t, err)
+
+	var anonymous bool
+	router := gin.New()
+	router.Use(authenticator.Identify())
+	router.Use(func(c *gin.Context) {
+		_, ok := UserID(c)
+		anonymous = !ok
+		c.Next()
+	})
+	router.Use(RejectInvalid())
+	router.GET("/read", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/read", nil)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	router.ServeHTTP(w, req)
+
+	assert.True(t, anonymous)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Bearer error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestNewAuthenticator_NoKeys(t *testing.T) {
+	_, err := NewAuthenticator(&config.Config{})
+	assert.ErrorIs(t, err, ErrNoKeys)
+}
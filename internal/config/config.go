@@ -1,7 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +18,47 @@ type Config struct {
 	DBName     string
 	SSLMode    string
 	ServerPort string
+
+	JWTSecret        string
+	JWTPublicKeyFile string
+	JWTJWKSFile      string
+	JWTIssuer        string
+	JWTAudience      string
+
+	TrashRetention time.Duration
+	PurgeInterval  time.Duration
+
+	// Rate limits are requests per minute with a burst allowance; a zero
+	// limit disables that class. RateLimitStore is "memory" or "postgres".
+	RateLimitRead       int
+	RateLimitReadBurst  int
+	RateLimitWrite      int
+	RateLimitWriteBurst int
+	RateLimitStore      string
+
+	// TrustedProxies lists the IPs and CIDRs allowed to set X-Forwarded-For.
+	// Empty means the client IP is always the peer address.
+	TrustedProxies []string
+
+	IdempotencyTTL time.Duration
+
+	// ReadinessTimeout bounds the database ping in /readyz. ShutdownDrainDelay
+	// is how long /readyz reports 503 before the server stops accepting
+	// connections.
+	ReadinessTimeout   time.Duration
+	ShutdownDrainDelay time.Duration
+
+	// TracesExporter is none, otlp, stdout or file; TracesFile is the output
+	// of the file exporter.
+	TracesExporter string
+	TracesFile     string
+
+	// AutoMigrate applies pending migrations at startup.
+	AutoMigrate bool
+
+	// SchemaCheck is off, warn or strict. Startup compares the GORM models
+	// with the database schema and logs drift; strict also fails /readyz.
+	SchemaCheck string
 }
 
 func LoadConfig() (*Config, error) {
@@ -20,6 +66,75 @@ func LoadConfig() (*Config, error) {
 	// 	return nil, fmt.Errorf("error loading config.env: %w", err)
 	// }
 
+	trashRetention, err := getDuration("TRASH_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	purgeInterval, err := getDuration("TRASH_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	readLimit, err := getInt("RATE_LIMIT_READ", 300)
+	if err != nil {
+		return nil, err
+	}
+	readBurst, err := getInt("RATE_LIMIT_READ_BURST", 60)
+	if err != nil {
+		return nil, err
+	}
+	writeLimit, err := getInt("RATE_LIMIT_WRITE", 30)
+	if err != nil {
+		return nil, err
+	}
+	writeBurst, err := getInt("RATE_LIMIT_WRITE_BURST", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimitStore := getEnv("RATE_LIMIT_STORE", "memory")
+	if rateLimitStore != "memory" && rateLimitStore != "postgres" {
+		return nil, fmt.Errorf("RATE_LIMIT_STORE must be memory or postgres, got %q", rateLimitStore)
+	}
+
+	trustedProxies, err := getAddressList("TRUSTED_PROXIES")
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyTTL, err := getDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	readinessTimeout, err := getDuration("READINESS_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	drainDelay, err := getNonNegativeDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	tracesExporter := getEnv("OTEL_TRACES_EXPORTER", "none")
+	switch tracesExporter {
+	case "none", "otlp", "stdout", "file":
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER must be none, otlp, stdout or file, got %q", tracesExporter)
+	}
+
+	autoMigrate, err := getBool("AUTO_MIGRATE", false)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaCheck := getEnv("SCHEMA_CHECK", "warn")
+	switch schemaCheck {
+	case "off", "warn", "strict":
+	default:
+		return nil, fmt.Errorf("SCHEMA_CHECK must be off, warn or strict, got %q", schemaCheck)
+	}
+
 	return &Config{
 		ENV:        getEnv("env", "local"),
 		DBHost:     getEnv("DB_HOST", "localhost"),
@@ -29,6 +144,34 @@ func LoadConfig() (*Config, error) {
 		DBName:     getEnv("DB_NAME", "wallet_db"),
 		SSLMode:    getEnv("DB_SSL_MODE", "disable"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+
+		JWTSecret:        getEnv("JWT_HS256_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_RS256_PUBLIC_KEY_FILE", ""),
+		JWTJWKSFile:      getEnv("JWT_JWKS_FILE", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+		JWTAudience:      getEnv("JWT_AUDIENCE", ""),
+
+		TrashRetention: trashRetention,
+		PurgeInterval:  purgeInterval,
+
+		RateLimitRead:       readLimit,
+		RateLimitReadBurst:  readBurst,
+		RateLimitWrite:      writeLimit,
+		RateLimitWriteBurst: writeBurst,
+		RateLimitStore:      rateLimitStore,
+
+		TrustedProxies: trustedProxies,
+
+		IdempotencyTTL: idempotencyTTL,
+
+		ReadinessTimeout:   readinessTimeout,
+		ShutdownDrainDelay: drainDelay,
+
+		TracesExporter: tracesExporter,
+		TracesFile:     getEnv("OTEL_TRACES_FILE", "traces.jsonl"),
+
+		AutoMigrate: autoMigrate,
+		SchemaCheck: schemaCheck,
 	}, nil
 }
 
@@ -47,3 +190,71 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getNonNegativeDuration is getDuration for settings where 0 turns the
+// behaviour off.
+func getNonNegativeDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration, got %q", key, value)
+	}
+	return d, nil
+}
+
+func getDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration, got %q", key, value)
+	}
+	return d, nil
+}
+
+func getInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+	}
+	return n, nil
+}
+
+// getAddressList parses a comma-separated list of IP addresses and CIDRs.
+func getAddressList(key string) ([]string, error) {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if net.ParseIP(item) == nil {
+			if _, _, err := net.ParseCIDR(item); err != nil {
+				return nil, fmt.Errorf("%s must list IP addresses or CIDRs, got %q", key, item)
+			}
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+func getBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+	return b, nil
+}
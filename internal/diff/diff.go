@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+type OpType string
+
+const (
+	Equal  OpType = "equal"
+	Insert OpType = "insert"
+	Delete OpType = "delete"
+)
+
+type Op struct {
+	Type  OpType   `json:"type"`
+	Lines []string `json:"lines"`
+}
+
+// MaxCells bounds the LCS table, which has one cell per pair of changed
+// lines. Lines common to the start and end of both texts don't count.
+const MaxCells = 1 << 20
+
+// ErrTooLarge is returned by Lines when the texts differ in too many lines to
+// diff within MaxCells.
+var ErrTooLarge = errors.New("texts are too large to diff")
+
+// Lines returns a line-based diff turning a into b. Consecutive lines of the
+// same kind are grouped into a single Op.
+func Lines(a, b string) ([]Op, error) {
+	x := splitLines(a)
+	y := splitLines(b)
+
+	var ops []Op
+	add := func(t OpType, line string) {
+		if n := len(ops); n > 0 && ops[n-1].Type == t {
+			ops[n-1].Lines = append(ops[n-1].Lines, line)
+			return
+		}
+		ops = append(ops, Op{Type: t, Lines: []string{line}})
+	}
+
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+	common := x[len(x)-suffix:]
+	for _, line := range x[:prefix] {
+		add(Equal, line)
+	}
+	x = x[prefix : len(x)-suffix]
+	y = y[prefix : len(y)-suffix]
+
+	if (len(x)+1)*(len(y)+1) > MaxCells {
+		return nil, ErrTooLarge
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			add(Equal, x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(Delete, x[i])
+			i++
+		default:
+			add(Insert, y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		add(Delete, x[i])
+	}
+	for ; j < len(y); j++ {
+		add(Insert, y[j])
+	}
+	for _, line := range common {
+		add(Equal, line)
+	}
+
+	return ops, nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,49 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	ops, err := Lines("a\nb\nc", "a\nx\nc\nd\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Op{
+		{Type: Equal, Lines: []string{"a"}},
+		{Type: Delete, Lines: []string{"b"}},
+		{Type: Insert, Lines: []string{"x"}},
+		{Type: Equal, Lines: []string{"c"}},
+		{Type: Insert, Lines: []string{"d"}},
+	}, ops)
+}
+
+func TestLines_Identical(t *testing.T) {
+	ops, err := Lines("same\ntext", "same\ntext")
+	require.NoError(t, err)
+	assert.Equal(t, []Op{{Type: Equal, Lines: []string{"same", "text"}}}, ops)
+
+	ops, err = Lines("", "")
+	require.NoError(t, err)
+	assert.Nil(t, ops)
+}
+
+func TestLines_TooLarge(t *testing.T) {
+	a := strings.Repeat("a\n", 2000)
+	b := strings.Repeat("b\n", 2000)
+
+	_, err := Lines(a, b)
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	// Unchanged lines around a small edit don't count towards the limit.
+	ops, err := Lines("head\n"+a+"tail", "head\n"+a+"changed")
+	require.NoError(t, err)
+	assert.Equal(t, []Op{
+		{Type: Equal, Lines: append([]string{"head"}, splitLines(a)...)},
+		{Type: Delete, Lines: []string{"tail"}},
+		{Type: Insert, Lines: []string{"changed"}},
+	}, ops)
+}
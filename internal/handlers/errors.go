@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func respondError(c *gin.Context, status int, message string) {
+	problem.Abort(c, status, message)
+}
+
+// respondBindError reports a request body that failed to decode or validate,
+// listing the invalid fields.
+func respondBindError(c *gin.Context, err error) {
+	problem.Write(c, problem.FromBindError(err))
+}
+
+// respondRepositoryError maps repository sentinel errors onto HTTP statuses so
+// every handler reports them the same way. notFound is the message for a
+// missing resource and failure the one for unexpected errors.
+func respondRepositoryError(c *gin.Context, err error, notFound, failure string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondError(c, http.StatusNotFound, notFound)
+	case errors.Is(err, repository.ErrVersionMismatch):
+		respondError(c, http.StatusPreconditionFailed, "Resource has been modified")
+	case errors.Is(err, repository.ErrQuestionLocked):
+		respondError(c, http.StatusConflict, "Question is locked")
+	case errors.Is(err, repository.ErrQuestionClosed):
+		respondError(c, http.StatusConflict, "Question is not open for answers")
+	case errors.Is(err, repository.ErrInvalidTransition):
+		respondError(c, http.StatusConflict, "Status transition is not allowed")
+	case errors.Is(err, repository.ErrConflict):
+		respondError(c, http.StatusConflict, "Request conflicts with the current state of the resource")
+	case errors.Is(err, repository.ErrUnavailable):
+		c.Header("Retry-After", "5")
+		respondError(c, http.StatusServiceUnavailable, "Database is unavailable")
+	case errors.Is(err, repository.ErrInvalidCursor):
+		respondError(c, http.StatusBadRequest, "Invalid cursor")
+	default:
+		respondError(c, http.StatusInternalServerError, failure)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// formatETag renders the version as a strong ETag. A non-empty variant is
+// appended to tell apart representations of the same version, such as a
+// question with and without comments.
+func formatETag(version uint, variant string) string {
+	tag := strconv.FormatUint(uint64(version), 10)
+	if variant != "" {
+		tag += "-" + variant
+	}
+	return `"` + tag + `"`
+}
+
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", formatETag(version, ""))
+}
+
+// parseIfMatch returns the versions listed in If-Match. A missing header or
+// "*" yields nil, meaning the write is unconditional. Weak and malformed tags
+// can never match a strong comparison, so they are dropped; when nothing
+// usable remains the result is empty but not nil, and matches no version.
+// The variant part of a tag is ignored, since every representation of a
+// version describes the same state.
+func parseIfMatch(c *gin.Context) []uint {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []uint{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		number, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+		v, err := strconv.ParseUint(number, 10, 32)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, uint(v))
+	}
+
+	return versions
+}
+
+// notModified reports whether If-None-Match lists the current ETag. It uses
+// weak comparison, as RFC 9110 requires for If-None-Match.
+func notModified(c *gin.Context, current string) bool {
+	header := strings.TrimSpace(c.GetHeader("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == current {
+			return true
+		}
+	}
+	return false
+}
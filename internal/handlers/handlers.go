@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
 )
 
 // type Handler struct {
@@ -17,21 +18,52 @@ import (
 //	}
 type Repository interface {
 	CreateQuestion(ctx context.Context, question *models.Question) error
-	GetQuestions(ctx context.Context) ([]models.Question, error)
-	GetQuestion(ctx context.Context, id uint) (*models.Question, error)
-	DeleteQuestion(ctx context.Context, id uint) error
+	GetQuestions(ctx context.Context, opts repository.QuestionListOptions) (*repository.QuestionPage, error)
+	GetQuestion(ctx context.Context, id uint, opts repository.QuestionDetailOptions) (*models.Question, error)
+	UpdateQuestion(ctx context.Context, id uint, upd repository.TextUpdate) (*models.Question, error)
+	SetAcceptedAnswer(ctx context.Context, questionID, answerID uint, accept bool, ifMatch []uint) (*models.Question, error)
+	ChangeQuestionStatus(ctx context.Context, id uint, change repository.StatusChange) (*models.Question, error)
+	GetQuestionStatusHistory(ctx context.Context, questionID uint) ([]models.QuestionStatusChange, error)
+	DeleteQuestion(ctx context.Context, id uint, ifMatch []uint) error
+	GetTrash(ctx context.Context, opts repository.TrashListOptions) (*repository.QuestionPage, error)
+	GetDeletedQuestion(ctx context.Context, id uint) (*models.Question, error)
+	RestoreQuestion(ctx context.Context, id uint, ifMatch []uint) (*models.Question, error)
 	QuestionExists(ctx context.Context, id uint) (bool, error)
-	CreateAnswer(ctx context.Context, answer *models.Answer) error
+	CreateAnswer(ctx context.Context, answer *models.Answer, ifMatch []uint) error
 	GetAnswer(ctx context.Context, id uint) (*models.Answer, error)
-	DeleteAnswer(ctx context.Context, id uint) error
+	UpdateAnswer(ctx context.Context, id uint, upd repository.TextUpdate) (*models.Answer, error)
+	DeleteAnswer(ctx context.Context, id uint, ifMatch []uint) error
+	GetQuestionText(ctx context.Context, id uint) (string, error)
+	GetQuestionRevisions(ctx context.Context, questionID uint) ([]models.QuestionRevision, error)
+	GetQuestionRevision(ctx context.Context, questionID, revisionID uint) (*models.QuestionRevision, error)
+	GetAnswerRevisions(ctx context.Context, answerID uint) ([]models.AnswerRevision, error)
+	GetAnswerRevision(ctx context.Context, answerID, revisionID uint) (*models.AnswerRevision, error)
+	VoteAnswer(ctx context.Context, answerID uint, userID string, value int, ifMatch []uint) (*models.Answer, error)
+	RetractVote(ctx context.Context, answerID uint, userID string, ifMatch []uint) (*models.Answer, error)
+	Search(ctx context.Context, opts repository.SearchOptions) (*repository.SearchResult, error)
+	GetTags(ctx context.Context, opts repository.TagListOptions) (*repository.TagPage, error)
+	CreateComment(ctx context.Context, comment *models.Comment, ifMatch []uint) error
+	GetQuestionComments(ctx context.Context, questionID uint) ([]models.Comment, error)
+	GetAnswerComments(ctx context.Context, answerID uint) ([]models.Comment, error)
+	GetComment(ctx context.Context, id uint) (*models.Comment, error)
+	DeleteComment(ctx context.Context, id uint) error
+	EnsureUser(ctx context.Context, user *models.User) error
+	GetUser(ctx context.Context, id string) (*models.User, error)
+	SetUserRole(ctx context.Context, id string, role models.Role) (*models.User, error)
+	GetAuditEvents(ctx context.Context, opts repository.AuditListOptions) (*repository.AuditPage, error)
+	GetUserAnswers(ctx context.Context, userID string, opts repository.AnswerListOptions) (*repository.AnswerPage, error)
 }
 
 type Handler struct {
 	repo Repository
+
+	// knownUsers caches users already stored by EnsureUser.
+	knownUsers *userCache
 }
 
 func NewHandler(repo Repository) *Handler {
 	return &Handler{
-		repo: repo,
+		repo:       repo,
+		knownUsers: newUserCache(userCacheSize, userCacheTTL),
 	}
 }
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAnswer_AuthorFromToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user-42"))
+	router.POST("/questions/:id/answers", handler.CreateAnswer)
+
+	mockRepo.On("QuestionExists", mock.Anything, uint(1)).Return(true, nil)
+	mockRepo.On("CreateAnswer", mock.Anything, mock.MatchedBy(func(a *models.Answer) bool {
+		return a.UserID == "user-42" && a.QuestionID == 1
+	}), []uint(nil)).Return(nil)
+
+	// user_id in the body must be ignored
+	jsonData, _ := json.Marshal(map[string]string{"user_id": "someone-else", "text": "Answer"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/answers", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.Answer
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-42", response.UserID)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateAnswer_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.POST("/questions/:id/answers", handler.CreateAnswer)
+
+	mockRepo.On("QuestionExists", mock.Anything, uint(1)).Return(true, nil)
+
+	jsonData, _ := json.Marshal(map[string]string{"text": "Answer"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/answers", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	mockRepo.AssertNotCalled(t, "CreateAnswer")
+}
+
+func TestVoteAnswer_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("voter"))
+	router.POST("/answers/:id/vote", handler.VoteAnswer)
+
+	mockRepo.On("VoteAnswer", mock.Anything, uint(7), "voter", -1, []uint(nil)).
+		Return(&models.Answer{ID: 7, Score: -1, Version: 2}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/answers/7/vote", bytes.NewBufferString(`{"value": -1}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+	var response models.Answer
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, response.Score)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVoteAnswer_InvalidValue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("voter"))
+	router.POST("/answers/:id/vote", handler.VoteAnswer)
+
+	for _, body := range []string{`{"value": 0}`, `{"value": 2}`, `{}`} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/answers/7/vote", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	mockRepo.AssertNotCalled(t, "VoteAnswer")
+}
+
+func TestVoteAnswer_PreconditionFailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("voter"))
+	router.POST("/answers/:id/vote", handler.VoteAnswer)
+
+	mockRepo.On("VoteAnswer", mock.Anything, uint(7), "voter", 1, []uint{2}).Return(nil, repository.ErrVersionMismatch)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/answers/7/vote", bytes.NewBufferString(`{"value": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2-comments"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
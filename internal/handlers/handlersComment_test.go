@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAnswerComment_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/answers/:id/comments", handler.CreateAnswerComment)
+
+	mockRepo.On("CreateComment", mock.Anything, mock.MatchedBy(func(c *models.Comment) bool {
+		return c.QuestionID == nil && c.AnswerID != nil && *c.AnswerID == 7 && c.UserID == "user1"
+	}), []uint(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/answers/7/comments", bytes.NewBufferString(`{"text": "Which Go version?"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateQuestionComment_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions/:id/comments", handler.CreateQuestionComment)
+
+	mockRepo.On("CreateComment", mock.Anything, mock.AnythingOfType("*models.Comment"), []uint(nil)).Return(repository.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/999/comments", bytes.NewBufferString(`{"text": "Clarify please"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteComment_OnlyAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user2"))
+	router.DELETE("/comments/:id", handler.DeleteComment)
+
+	mockRepo.On("GetComment", mock.Anything, uint(3)).Return(&models.Comment{ID: 3, UserID: "user1"}, nil)
+	mockRepo.On("GetUser", mock.Anything, "user2").Return(&models.User{ID: "user2", Role: models.RoleUser}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/comments/3", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "DeleteComment")
+}
+
+func TestGetQuestion_IncludeComments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id", handler.GetQuestion)
+
+	opts := repository.QuestionDetailOptions{AnswerSort: repository.SortAnswersByOldest, IncludeComments: true}
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), opts).Return(&models.Question{ID: 1, Version: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/1?include=comments", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/NKV510/question-answer-api/internal/auth"
 	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/NKV510/question-answer-api/internal/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,26 +28,45 @@ func (m *MockRepository) CreateQuestion(ctx context.Context, question *models.Qu
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetQuestions(ctx context.Context) ([]models.Question, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]models.Question), args.Error(1)
+func (m *MockRepository) GetQuestions(ctx context.Context, opts repository.QuestionListOptions) (*repository.QuestionPage, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.QuestionPage), args.Error(1)
 }
 
-func (m *MockRepository) GetQuestion(ctx context.Context, id uint) (*models.Question, error) {
-	args := m.Called(ctx, id)
+func (m *MockRepository) GetQuestion(ctx context.Context, id uint, opts repository.QuestionDetailOptions) (*models.Question, error) {
+	args := m.Called(ctx, id, opts)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Question), args.Error(1)
 }
 
-func (m *MockRepository) DeleteQuestion(ctx context.Context, id uint) error {
-	args := m.Called(ctx, id)
+func (m *MockRepository) UpdateQuestion(ctx context.Context, id uint, upd repository.TextUpdate) (*models.Question, error) {
+	args := m.Called(ctx, id, upd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockRepository) SetAcceptedAnswer(ctx context.Context, questionID, answerID uint, accept bool, ifMatch []uint) (*models.Question, error) {
+	args := m.Called(ctx, questionID, answerID, accept, ifMatch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockRepository) DeleteQuestion(ctx context.Context, id uint, ifMatch []uint) error {
+	args := m.Called(ctx, id, ifMatch)
 	return args.Error(0)
 }
 
-func (m *MockRepository) CreateAnswer(ctx context.Context, answer *models.Answer) error {
-	args := m.Called(ctx, answer)
+func (m *MockRepository) CreateAnswer(ctx context.Context, answer *models.Answer, ifMatch []uint) error {
+	args := m.Called(ctx, answer, ifMatch)
 	return args.Error(0)
 }
 
@@ -54,16 +78,206 @@ func (m *MockRepository) GetAnswer(ctx context.Context, id uint) (*models.Answer
 	return args.Get(0).(*models.Answer), args.Error(1)
 }
 
-func (m *MockRepository) DeleteAnswer(ctx context.Context, id uint) error {
+func (m *MockRepository) UpdateAnswer(ctx context.Context, id uint, upd repository.TextUpdate) (*models.Answer, error) {
+	args := m.Called(ctx, id, upd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Answer), args.Error(1)
+}
+
+func (m *MockRepository) DeleteAnswer(ctx context.Context, id uint, ifMatch []uint) error {
+	args := m.Called(ctx, id, ifMatch)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetQuestionText(ctx context.Context, id uint) (string, error) {
 	args := m.Called(ctx, id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) GetQuestionRevisions(ctx context.Context, questionID uint) ([]models.QuestionRevision, error) {
+	args := m.Called(ctx, questionID)
+	return args.Get(0).([]models.QuestionRevision), args.Error(1)
+}
+
+func (m *MockRepository) GetQuestionRevision(ctx context.Context, questionID, revisionID uint) (*models.QuestionRevision, error) {
+	args := m.Called(ctx, questionID, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QuestionRevision), args.Error(1)
+}
+
+func (m *MockRepository) VoteAnswer(ctx context.Context, answerID uint, userID string, value int, ifMatch []uint) (*models.Answer, error) {
+	args := m.Called(ctx, answerID, userID, value, ifMatch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Answer), args.Error(1)
+}
+
+func (m *MockRepository) RetractVote(ctx context.Context, answerID uint, userID string, ifMatch []uint) (*models.Answer, error) {
+	args := m.Called(ctx, answerID, userID, ifMatch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Answer), args.Error(1)
+}
+
+func (m *MockRepository) GetAnswerRevisions(ctx context.Context, answerID uint) ([]models.AnswerRevision, error) {
+	args := m.Called(ctx, answerID)
+	return args.Get(0).([]models.AnswerRevision), args.Error(1)
+}
+
+func (m *MockRepository) GetAnswerRevision(ctx context.Context, answerID, revisionID uint) (*models.AnswerRevision, error) {
+	args := m.Called(ctx, answerID, revisionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AnswerRevision), args.Error(1)
+}
+
+func (m *MockRepository) Search(ctx context.Context, opts repository.SearchOptions) (*repository.SearchResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.SearchResult), args.Error(1)
+}
+
+func (m *MockRepository) GetTags(ctx context.Context, opts repository.TagListOptions) (*repository.TagPage, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TagPage), args.Error(1)
+}
+
+func (m *MockRepository) CreateComment(ctx context.Context, comment *models.Comment, ifMatch []uint) error {
+	args := m.Called(ctx, comment, ifMatch)
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetQuestionComments(ctx context.Context, questionID uint) ([]models.Comment, error) {
+	args := m.Called(ctx, questionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Comment), args.Error(1)
+}
+
+func (m *MockRepository) GetAnswerComments(ctx context.Context, answerID uint) ([]models.Comment, error) {
+	args := m.Called(ctx, answerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Comment), args.Error(1)
+}
+
+func (m *MockRepository) GetComment(ctx context.Context, id uint) (*models.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockRepository) DeleteComment(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetTrash(ctx context.Context, opts repository.TrashListOptions) (*repository.QuestionPage, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.QuestionPage), args.Error(1)
+}
+
+func (m *MockRepository) GetDeletedQuestion(ctx context.Context, id uint) (*models.Question, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockRepository) RestoreQuestion(ctx context.Context, id uint, ifMatch []uint) (*models.Question, error) {
+	args := m.Called(ctx, id, ifMatch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockRepository) ChangeQuestionStatus(ctx context.Context, id uint, change repository.StatusChange) (*models.Question, error) {
+	args := m.Called(ctx, id, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockRepository) GetQuestionStatusHistory(ctx context.Context, questionID uint) ([]models.QuestionStatusChange, error) {
+	args := m.Called(ctx, questionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.QuestionStatusChange), args.Error(1)
+}
+
+func (m *MockRepository) SetUserRole(ctx context.Context, id string, role models.Role) (*models.User, error) {
+	args := m.Called(ctx, id, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockRepository) GetAuditEvents(ctx context.Context, opts repository.AuditListOptions) (*repository.AuditPage, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.AuditPage), args.Error(1)
+}
+
+func (m *MockRepository) EnsureUser(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetUser(ctx context.Context, id string) (*models.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockRepository) GetUserAnswers(ctx context.Context, userID string, opts repository.AnswerListOptions) (*repository.AnswerPage, error) {
+	args := m.Called(ctx, userID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.AnswerPage), args.Error(1)
+}
+
 func (m *MockRepository) QuestionExists(ctx context.Context, id uint) (bool, error) {
 	args := m.Called(ctx, id)
 	return args.Bool(0), args.Error(1)
 }
 
+// withUser authenticates every request as userID, standing in for the JWT middleware.
+func withUser(userID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth.SetUserID(c, userID)
+		c.Next()
+	}
+}
+
 func TestCreateQuestion_Success(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -71,6 +285,7 @@ func TestCreateQuestion_Success(t *testing.T) {
 	mockRepo := new(MockRepository)
 	handler := NewHandler(mockRepo)
 
+	router.Use(withUser("user1"))
 	router.POST("/questions", handler.CreateQuestion)
 
 	// Mock expectations
@@ -98,6 +313,9 @@ func TestCreateQuestion_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, uint(1), response.ID)
 	assert.Equal(t, "Test question?", response.Text)
+	if assert.NotNil(t, response.AuthorID) {
+		assert.Equal(t, "user1", *response.AuthorID)
+	}
 
 	mockRepo.AssertExpectations(t)
 }
@@ -122,6 +340,13 @@ func TestCreateQuestion_InvalidInput(t *testing.T) {
 
 	// Assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+	var response problem.Problem
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, problem.TypeValidation, response.Type)
+	assert.Equal(t, []problem.FieldError{{Field: "text", Rule: "required", Message: "is required"}}, response.Errors)
 
 	// Проверяем что метод репозитория не вызывался
 	mockRepo.AssertNotCalled(t, "CreateQuestion")
@@ -137,11 +362,15 @@ func TestGetQuestions_Success(t *testing.T) {
 	router.GET("/questions", handler.GetQuestions)
 
 	// Mock expectations
-	expectedQuestions := []models.Question{
-		{ID: 1, Text: "Question 1?"},
-		{ID: 2, Text: "Question 2?"},
+	expectedPage := &repository.QuestionPage{
+		Items: []models.Question{
+			{ID: 1, Text: "Question 1?"},
+			{ID: 2, Text: "Question 2?"},
+		},
+		Total:      5,
+		NextCursor: "next",
 	}
-	mockRepo.On("GetQuestions", mock.Anything).Return(expectedQuestions, nil)
+	mockRepo.On("GetQuestions", mock.Anything, mock.Anything).Return(expectedPage, nil)
 
 	// Test
 	w := httptest.NewRecorder()
@@ -151,16 +380,63 @@ func TestGetQuestions_Success(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response []models.Question
+	var response repository.QuestionPage
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Len(t, response, 2)
-	assert.Equal(t, uint(1), response[0].ID)
-	assert.Equal(t, "Question 1?", response[0].Text)
+	assert.Len(t, response.Items, 2)
+	assert.Equal(t, uint(1), response.Items[0].ID)
+	assert.Equal(t, "Question 1?", response.Items[0].Text)
+	assert.Equal(t, int64(5), response.Total)
+	assert.Equal(t, "next", response.NextCursor)
 
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetQuestions_QueryOptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions", handler.GetQuestions)
+
+	after := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	expectedOpts := repository.QuestionListOptions{
+		Limit:        5,
+		Offset:       10,
+		CreatedAfter: &after,
+		Sort:         repository.SortByAnswerCount,
+		Desc:         false,
+	}
+	mockRepo.On("GetQuestions", mock.Anything, expectedOpts).Return(&repository.QuestionPage{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions?limit=5&offset=10&sort=answers&order=asc&created_after=2025-11-01T00:00:00Z", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetQuestions_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions", handler.GetQuestions)
+
+	for _, query := range []string{"limit=0", "limit=1000", "offset=-1", "sort=text", "order=up", "created_after=yesterday"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/questions?"+query, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	mockRepo.AssertNotCalled(t, "GetQuestions")
+}
+
 func TestGetQuestions_Error(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -171,7 +447,7 @@ func TestGetQuestions_Error(t *testing.T) {
 	router.GET("/questions", handler.GetQuestions)
 
 	// Mock expectations - возвращаем ошибку
-	mockRepo.On("GetQuestions", mock.Anything).Return([]models.Question{}, assert.AnError)
+	mockRepo.On("GetQuestions", mock.Anything, mock.Anything).Return(nil, assert.AnError)
 
 	// Test
 	w := httptest.NewRecorder()
@@ -181,10 +457,10 @@ func TestGetQuestions_Error(t *testing.T) {
 	// Assert
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response map[string]string
+	var response problem.Problem
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "Failed to fetch questions")
+	assert.Contains(t, response.Detail, "Failed to fetch questions")
 
 	mockRepo.AssertExpectations(t)
 }
@@ -205,7 +481,7 @@ func TestGetQuestion_Success(t *testing.T) {
 			{ID: 1, QuestionID: 1, UserID: "user1", Text: "Answer 1"},
 		},
 	}
-	mockRepo.On("GetQuestion", mock.Anything, uint(1)).Return(expectedQuestion, nil)
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).Return(expectedQuestion, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/questions/1", nil)
@@ -231,7 +507,7 @@ func TestGetQuestion_NotFound(t *testing.T) {
 
 	router.GET("/questions/:id", handler.GetQuestion)
 
-	mockRepo.On("GetQuestion", mock.Anything, uint(999)).Return(nil, assert.AnError)
+	mockRepo.On("GetQuestion", mock.Anything, uint(999), mock.Anything).Return(nil, repository.ErrNotFound)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/questions/999", nil)
@@ -239,10 +515,10 @@ func TestGetQuestion_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	var response map[string]string
+	var response problem.Problem
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Equal(t, "Question not found", response["error"])
+	assert.Equal(t, "Question not found", response.Detail)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -261,10 +537,241 @@ func TestGetQuestion_InvalidID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response map[string]string
+	var response problem.Problem
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Contains(t, response["error"], "Invalid question ID")
+	assert.Contains(t, response.Detail, "Invalid question ID")
 
 	mockRepo.AssertNotCalled(t, "GetQuestion")
 }
+
+func TestGetQuestion_NotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id", handler.GetQuestion)
+
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).Return(&models.Question{ID: 1, Text: "Test question?", Version: 3}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/1", nil)
+	req.Header.Set("If-None-Match", `"3"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.String())
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateQuestion_PreconditionFailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("moderator"))
+	router.PATCH("/questions/:id", handler.UpdateQuestion)
+
+	author := "moderator"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author, Version: 3}, nil)
+	upd := repository.TextUpdate{Text: "Updated?", Editor: "moderator", IfMatch: []uint{2}}
+	mockRepo.On("UpdateQuestion", mock.Anything, uint(1), upd).Return(nil, repository.ErrVersionMismatch)
+
+	jsonData, _ := json.Marshal(map[string]string{"text": "Updated?"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/questions/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetQuestion_ETagVariesByRepresentation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id", handler.GetQuestion)
+
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, Version: 3}, nil)
+
+	etags := map[string]string{
+		"/questions/1":                             `"3"`,
+		"/questions/1?sort=oldest":                 `"3"`,
+		"/questions/1?include=comments":            `"3-comments"`,
+		"/questions/1?sort=score&include=comments": `"3-score-comments"`,
+		"/questions/1?sort=newest":                 `"3-newest"`,
+	}
+	for path, want := range etags {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("If-None-Match", `"3"`)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Header().Get("ETag"), path)
+		if want == `"3"` {
+			assert.Equal(t, http.StatusNotModified, w.Code, path)
+		} else {
+			assert.Equal(t, http.StatusOK, w.Code, path)
+		}
+	}
+}
+
+func TestDeleteQuestion_UnusableIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("author"))
+	router.DELETE("/questions/:id", handler.DeleteQuestion)
+
+	author := "author"
+	mockRepo.On("GetQuestion", mock.Anything, uint(999), mock.Anything).Return(nil, repository.ErrNotFound)
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author, Version: 3}, nil)
+	mockRepo.On("DeleteQuestion", mock.Anything, uint(1), []uint{}).Return(repository.ErrVersionMismatch)
+
+	for path, want := range map[string]int{
+		"/questions/999": http.StatusNotFound,
+		"/questions/1":   http.StatusPreconditionFailed,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", path, nil)
+		req.Header.Set("If-Match", `W/"3"`)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, path)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetQuestion_DatabaseUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id", handler.GetQuestion)
+
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(nil, fmt.Errorf("%w: connection refused", repository.ErrUnavailable))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteQuestion_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.DELETE("/questions/:id", handler.DeleteQuestion)
+
+	mockRepo.On("GetQuestion", mock.Anything, uint(999), repository.QuestionDetailOptions{}).Return(nil, repository.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/questions/999", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DeleteQuestion")
+}
+
+func TestGetQuestion_SortAnswers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id", handler.GetQuestion)
+
+	opts := repository.QuestionDetailOptions{AnswerSort: repository.SortAnswersByScore}
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), opts).Return(&models.Question{ID: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/1?sort=score", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/questions/1?sort=random", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAcceptAnswer_OnlyAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("someone-else"))
+	router.POST("/questions/:id/accept/:answer_id", handler.AcceptAnswer)
+
+	author := "author"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/accept/2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mockRepo.AssertNotCalled(t, "SetAcceptedAnswer")
+}
+
+func TestAcceptAnswer_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("author"))
+	router.POST("/questions/:id/accept/:answer_id", handler.AcceptAnswer)
+
+	author := "author"
+	accepted := uint(2)
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("SetAcceptedAnswer", mock.Anything, uint(1), uint(2), true, []uint(nil)).
+		Return(&models.Question{ID: 1, AuthorID: &author, AcceptedAnswerID: &accepted}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/accept/2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Question
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	if assert.NotNil(t, response.AcceptedAnswerID) {
+		assert.Equal(t, uint(2), *response.AcceptedAnswerID)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/diff"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateQuestion_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("moderator"))
+	router.PATCH("/questions/:id", handler.UpdateQuestion)
+
+	author := "moderator"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	upd := repository.TextUpdate{Text: "Updated?", Editor: "moderator"}
+	mockRepo.On("UpdateQuestion", mock.Anything, uint(1), upd).
+		Return(&models.Question{ID: 1, Text: "Updated?"}, nil)
+
+	jsonData, _ := json.Marshal(map[string]string{"text": "Updated?"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/questions/1", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Question
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated?", response.Text)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateQuestion_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("moderator"))
+	router.PATCH("/questions/:id", handler.UpdateQuestion)
+
+	mockRepo.On("GetQuestion", mock.Anything, uint(999), mock.Anything).Return(nil, repository.ErrNotFound)
+
+	jsonData, _ := json.Marshal(map[string]string{"text": "Updated?"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/questions/999", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateQuestion")
+}
+
+func TestDiffQuestionRevisions_AgainstCurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id/revisions/diff", handler.DiffQuestionRevisions)
+
+	mockRepo.On("GetQuestionRevision", mock.Anything, uint(1), uint(2)).
+		Return(&models.QuestionRevision{ID: 2, QuestionID: 1, PreviousText: "old title\nbody"}, nil)
+	mockRepo.On("GetQuestionText", mock.Anything, uint(1)).Return("new title\nbody", nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/1/revisions/diff?from=2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response RevisionDiffResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "current", response.To)
+	assert.Equal(t, []diff.Op{
+		{Type: diff.Delete, Lines: []string{"old title"}},
+		{Type: diff.Insert, Lines: []string{"new title"}},
+		{Type: diff.Equal, Lines: []string{"body"}},
+	}, response.Changes)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDiffQuestionRevisions_TooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions/:id/revisions/diff", handler.DiffQuestionRevisions)
+
+	mockRepo.On("GetQuestionRevision", mock.Anything, uint(1), uint(2)).
+		Return(&models.QuestionRevision{ID: 2, QuestionID: 1, PreviousText: strings.Repeat("a\n", 2000)}, nil)
+	mockRepo.On("GetQuestionText", mock.Anything, uint(1)).Return(strings.Repeat("b\n", 2000), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/1/revisions/diff?from=2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestDiffAnswerRevisions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/answers/:id/revisions/diff", handler.DiffAnswerRevisions)
+
+	mockRepo.On("GetAnswerRevision", mock.Anything, uint(3), uint(4)).
+		Return(&models.AnswerRevision{ID: 4, AnswerID: 3, PreviousText: "first"}, nil)
+	mockRepo.On("GetAnswerRevision", mock.Anything, uint(3), uint(5)).
+		Return(&models.AnswerRevision{ID: 5, AnswerID: 3, PreviousText: "first\nsecond"}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/answers/3/revisions/diff?from=4&to=5", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response RevisionDiffResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []diff.Op{
+		{Type: diff.Equal, Lines: []string{"first"}},
+		{Type: diff.Insert, Lines: []string{"second"}},
+	}, response.Changes)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDiffAnswerRevisions_RevisionNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/answers/:id/revisions/diff", handler.DiffAnswerRevisions)
+
+	mockRepo.On("GetAnswerRevision", mock.Anything, uint(3), uint(9)).Return(nil, repository.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/answers/3/revisions/diff?from=9", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
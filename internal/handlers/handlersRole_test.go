@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRoleAtLeast(t *testing.T) {
+	assert.True(t, models.RoleAdmin.AtLeast(models.RoleModerator))
+	assert.True(t, models.RoleModerator.AtLeast(models.RoleModerator))
+	assert.False(t, models.RoleUser.AtLeast(models.RoleModerator))
+	assert.False(t, models.Role("root").AtLeast(models.RoleUser))
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name   string
+		user   string
+		role   models.Role
+		status int
+	}{
+		{name: "anonymous", status: http.StatusUnauthorized},
+		{name: "user", user: "user1", role: models.RoleUser, status: http.StatusForbidden},
+		{name: "moderator", user: "mod", role: models.RoleModerator, status: http.StatusOK},
+		{name: "admin", user: "root", role: models.RoleAdmin, status: http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			mockRepo := new(MockRepository)
+			handler := NewHandler(mockRepo)
+
+			if tc.user != "" {
+				router.Use(withUser(tc.user))
+				mockRepo.On("GetUser", mock.Anything, tc.user).Return(&models.User{ID: tc.user, Role: tc.role}, nil)
+			}
+			router.GET("/protected", handler.RequireRole(models.RoleModerator), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestDeleteAnswer_Moderator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("mod"))
+	router.DELETE("/answers/:id", handler.DeleteAnswer)
+
+	mockRepo.On("GetAnswer", mock.Anything, uint(5)).Return(&models.Answer{ID: 5, UserID: "user1"}, nil)
+	mockRepo.On("GetUser", mock.Anything, "mod").Return(&models.User{ID: "mod", Role: models.RoleModerator}, nil)
+	mockRepo.On("DeleteAnswer", mock.Anything, uint(5), []uint(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/answers/5", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteQuestion_OwnByAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.DELETE("/questions/:id", handler.DeleteQuestion)
+
+	author := "user1"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), repository.QuestionDetailOptions{}).Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("DeleteQuestion", mock.Anything, uint(1), []uint(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/questions/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetUser")
+}
+
+func TestUpdateQuestion_NonAuthorForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user2"))
+	router.PATCH("/questions/:id", handler.UpdateQuestion)
+
+	author := "user1"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), repository.QuestionDetailOptions{}).Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("GetUser", mock.Anything, "user2").Return(&models.User{ID: "user2", Role: models.RoleUser}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/questions/1", bytes.NewBufferString(`{"text": "Hijacked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateQuestion")
+}
+
+func TestUpdateAnswer_NonAuthorForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user2"))
+	router.PATCH("/answers/:id", handler.UpdateAnswer)
+
+	mockRepo.On("GetAnswer", mock.Anything, uint(7)).Return(&models.Answer{ID: 7, UserID: "user1"}, nil)
+	mockRepo.On("GetUser", mock.Anything, "user2").Return(&models.User{ID: "user2", Role: models.RoleUser}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/answers/7", bytes.NewBufferString(`{"text": "Hijacked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateAnswer")
+}
+
+func TestUpdateAnswer_Moderator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("mod"))
+	router.PATCH("/answers/:id", handler.UpdateAnswer)
+
+	mockRepo.On("GetAnswer", mock.Anything, uint(7)).Return(&models.Answer{ID: 7, UserID: "user1"}, nil)
+	mockRepo.On("GetUser", mock.Anything, "mod").Return(&models.User{ID: "mod", Role: models.RoleModerator}, nil)
+	mockRepo.On("UpdateAnswer", mock.Anything, uint(7), repository.TextUpdate{Text: "Fixed typo", Editor: "mod"}).
+		Return(&models.Answer{ID: 7, UserID: "user1", Text: "Fixed typo", Version: 2}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/answers/7", bytes.NewBufferString(`{"text": "Fixed typo"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGrantRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("root"))
+	router.PUT("/admin/users/:id/role", handler.GrantRole)
+
+	mockRepo.On("SetUserRole", mock.Anything, "user1", models.RoleModerator).
+		Return(&models.User{ID: "user1", Role: models.RoleModerator}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/admin/users/user1/role", bytes.NewBufferString(`{"role": "moderator"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/admin/users/user1/role", bytes.NewBufferString(`{"role": "root"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/admin/users/root/role", bytes.NewBufferString(`{"role": "user"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAuditEvents_Filters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/admin/audit", handler.GetAuditEvents)
+
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	opts := repository.AuditListOptions{
+		Actor:      "mod",
+		Action:     "question.delete",
+		EntityType: "question",
+		EntityID:   "7",
+		From:       &from,
+		Limit:      50,
+	}
+	mockRepo.On("GetAuditEvents", mock.Anything, opts).Return(&repository.AuditPage{Items: []models.AuditEvent{}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/audit?actor=mod&action=question.delete&entity_type=question&entity_id=7&from=2025-12-01T00:00:00Z&limit=50", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/audit?to=yesterday", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
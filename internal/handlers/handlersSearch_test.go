@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearch_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/search", handler.Search)
+
+	expectedOpts := repository.SearchOptions{
+		Query: "горутины",
+		Type:  repository.SearchAnswers,
+		Limit: 10,
+	}
+	expectedResult := &repository.SearchResult{
+		Items: []repository.SearchHit{
+			{Type: repository.SearchAnswers, ID: 3, QuestionID: 1, Rank: 0.6, Snippet: "Используйте <mark>горутины</mark>"},
+		},
+		Total: 1,
+	}
+	mockRepo.On("Search", mock.Anything, expectedOpts).Return(expectedResult, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?q=%D0%B3%D0%BE%D1%80%D1%83%D1%82%D0%B8%D0%BD%D1%8B&type=answer&limit=10", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response repository.SearchResult
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Items, 1)
+	assert.Equal(t, uint(1), response.Items[0].QuestionID)
+	assert.Contains(t, response.Items[0].Snippet, "<mark>")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearch_InvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/search", handler.Search)
+
+	for _, query := range []string{"", "q=", "q=go&type=comment", "q=go&limit=0"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/search?"+query, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	mockRepo.AssertNotCalled(t, "Search")
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAnswer_QuestionClosed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions/:id/answers", handler.CreateAnswer)
+
+	mockRepo.On("QuestionExists", mock.Anything, uint(1)).Return(true, nil)
+	mockRepo.On("CreateAnswer", mock.Anything, mock.AnythingOfType("*models.Answer"), []uint(nil)).Return(repository.ErrQuestionClosed)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/answers", bytes.NewBufferString(`{"text": "Late answer"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestChangeQuestionStatus_Close(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("mod"))
+	router.POST("/questions/:id/status", handler.ChangeQuestionStatus)
+
+	reason := "Off-topic"
+	change := repository.StatusChange{Status: models.QuestionClosed, Reason: reason, ChangedBy: "mod", IfMatch: []uint{2}}
+	mockRepo.On("ChangeQuestionStatus", mock.Anything, uint(1), change).
+		Return(&models.Question{ID: 1, Status: models.QuestionClosed, CloseReason: &reason, Version: 3}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/status", bytes.NewBufferString(`{"status": "closed", "reason": "Off-topic"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestChangeQuestionStatus_InvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions/:id/status", handler.ChangeQuestionStatus)
+
+	for _, body := range []string{
+		`{"status": "archived"}`,
+		`{"status": "closed"}`,
+		`{"status": "duplicate"}`,
+		`{"status": "duplicate", "duplicate_of": 1}`,
+		`{"status": "open", "duplicate_of": 2}`,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/questions/1/status", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	mockRepo.AssertNotCalled(t, "ChangeQuestionStatus")
+}
+
+func TestChangeQuestionStatus_InvalidTransition(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions/:id/status", handler.ChangeQuestionStatus)
+
+	mockRepo.On("ChangeQuestionStatus", mock.Anything, uint(1), mock.AnythingOfType("repository.StatusChange")).
+		Return(nil, repository.ErrInvalidTransition)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/status", bytes.NewBufferString(`{"status": "locked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLockedQuestion_RejectsWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.PATCH("/questions/:id", handler.UpdateQuestion)
+	router.PATCH("/answers/:id", handler.UpdateAnswer)
+	router.POST("/questions/:id/comments", handler.CreateQuestionComment)
+	router.POST("/answers/:id/vote", handler.VoteAnswer)
+
+	author := "user1"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("GetAnswer", mock.Anything, uint(2)).Return(&models.Answer{ID: 2, UserID: author}, nil)
+	mockRepo.On("UpdateQuestion", mock.Anything, uint(1), mock.Anything).Return(nil, repository.ErrQuestionLocked)
+	mockRepo.On("UpdateAnswer", mock.Anything, uint(2), mock.Anything).Return(nil, repository.ErrQuestionLocked)
+	mockRepo.On("CreateComment", mock.Anything, mock.Anything, []uint(nil)).Return(repository.ErrQuestionLocked)
+	mockRepo.On("VoteAnswer", mock.Anything, uint(2), "user1", 1, []uint(nil)).Return(nil, repository.ErrQuestionLocked)
+
+	requests := []struct {
+		method, path, body string
+	}{
+		{"PATCH", "/questions/1", `{"text": "Edited"}`},
+		{"PATCH", "/answers/2", `{"text": "Edited"}`},
+		{"POST", "/questions/1/comments", `{"text": "Comment"}`},
+		{"POST", "/answers/2/vote", `{"value": 1}`},
+	}
+	for _, r := range requests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(r.method, r.path, bytes.NewBufferString(r.body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code, r.path)
+		assert.Contains(t, w.Body.String(), "Question is locked", r.path)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAcceptAnswer_LockedQuestion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("author"))
+	router.POST("/questions/:id/accept/:answer_id", handler.AcceptAnswer)
+	router.DELETE("/questions/:id/accept/:answer_id", handler.UnacceptAnswer)
+
+	author := "author"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author, Status: models.QuestionLocked}, nil)
+	mockRepo.On("SetAcceptedAnswer", mock.Anything, uint(1), uint(2), mock.Anything, []uint(nil)).
+		Return(nil, repository.ErrQuestionLocked)
+
+	for _, method := range []string{"POST", "DELETE"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/questions/1/accept/2", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code, method)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUnacceptAnswer_NotAccepted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("author"))
+	router.DELETE("/questions/:id/accept/:answer_id", handler.UnacceptAnswer)
+
+	author := "author"
+	mockRepo.On("GetQuestion", mock.Anything, uint(1), mock.Anything).
+		Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("SetAcceptedAnswer", mock.Anything, uint(1), uint(2), false, []uint(nil)).
+		Return(nil, repository.ErrNotAccepted)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/questions/1/accept/2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Answer is not accepted")
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]string{" Go ", "PostgreSQL", "go", "c++"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "postgresql", "c++"}, tags)
+
+	for _, raw := range []string{"", "   ", "has space", "x/y", "abcdefghijklmnopqrstuvwxyz0123456789"} {
+		_, err := normalizeTags([]string{raw})
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestCreateQuestion_WithTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions", handler.CreateQuestion)
+
+	mockRepo.On("CreateQuestion", mock.Anything, mock.MatchedBy(func(q *models.Question) bool {
+		return len(q.Tags) == 2 && q.Tags[0].Name == "go" && q.Tags[1].Name == "postgres"
+	})).Return(nil)
+
+	body, _ := json.Marshal(CreateQuestionRequest{Text: "Question", Tags: []string{"Go", "postgres", "GO"}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateQuestion_TooManyTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions", handler.CreateQuestion)
+
+	body, _ := json.Marshal(CreateQuestionRequest{Text: "Question", Tags: []string{"a", "b", "c", "d", "e", "f"}})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "CreateQuestion")
+}
+
+func TestGetQuestions_TagFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/questions", handler.GetQuestions)
+
+	expectedOpts := repository.QuestionListOptions{
+		Limit:   repository.DefaultPageLimit,
+		Tags:    []string{"go", "postgres"},
+		TagMode: repository.TagModeAny,
+		Sort:    repository.SortByCreatedAt,
+		Desc:    true,
+	}
+	mockRepo.On("GetQuestions", mock.Anything, expectedOpts).Return(&repository.QuestionPage{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions?tag=Go&tag=postgres&tag_mode=any", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/questions?tag=go&tag_mode=xor", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTags_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/tags", handler.GetTags)
+
+	page := &repository.TagPage{
+		Items: []models.Tag{{ID: 1, Name: "go", UsageCount: 3}, {ID: 2, Name: "rust"}},
+		Total: 2,
+	}
+	mockRepo.On("GetTags", mock.Anything, repository.TagListOptions{Limit: 10}).Return(page, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tags?limit=10", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response repository.TagPage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(3), response.Items[0].UsageCount)
+	assert.Contains(t, w.Body.String(), `"usage_count":0`)
+	mockRepo.AssertExpectations(t)
+}
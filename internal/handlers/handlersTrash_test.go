@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTrash_OwnQuestions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.GET("/trash", handler.GetTrash)
+
+	mockRepo.On("GetUser", mock.Anything, "user1").Return(&models.User{ID: "user1", Role: models.RoleUser}, nil)
+	opts := repository.TrashListOptions{AuthorID: "user1", Limit: repository.DefaultPageLimit}
+	mockRepo.On("GetTrash", mock.Anything, opts).Return(&repository.QuestionPage{Items: []models.Question{}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/trash", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreQuestion_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions/:id/restore", handler.RestoreQuestion)
+
+	author := "user1"
+	mockRepo.On("GetDeletedQuestion", mock.Anything, uint(1)).Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("RestoreQuestion", mock.Anything, uint(1), []uint(nil)).Return(&models.Question{ID: 1, AuthorID: &author, Version: 4}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/restore", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreQuestion_NotInTrash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user1"))
+	router.POST("/questions/:id/restore", handler.RestoreQuestion)
+
+	mockRepo.On("GetDeletedQuestion", mock.Anything, uint(2)).Return(nil, repository.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/2/restore", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "RestoreQuestion")
+}
+
+func TestRestoreQuestion_OnlyAuthorOrModerator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("user2"))
+	router.POST("/questions/:id/restore", handler.RestoreQuestion)
+
+	author := "user1"
+	mockRepo.On("GetDeletedQuestion", mock.Anything, uint(1)).Return(&models.Question{ID: 1, AuthorID: &author}, nil)
+	mockRepo.On("GetUser", mock.Anything, "user2").Return(&models.User{ID: "user2", Role: models.RoleUser}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions/1/restore", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "RestoreQuestion")
+}
+
+func TestGetTrash_ModeratorSeesAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(withUser("mod"))
+	router.GET("/trash", handler.GetTrash)
+
+	mockRepo.On("GetUser", mock.Anything, "mod").Return(&models.User{ID: "mod", Role: models.RoleModerator}, nil)
+	opts := repository.TrashListOptions{Limit: repository.DefaultPageLimit}
+	mockRepo.On("GetTrash", mock.Anything, opts).Return(&repository.QuestionPage{Items: []models.Question{}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/trash", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
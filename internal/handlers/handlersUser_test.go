@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEnsureUser_RegistersOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(func(c *gin.Context) {
+		auth.SetIdentity(c, auth.Identity{Subject: "sub-1", Name: "Ivan", Username: "ivan"})
+		c.Next()
+	})
+	router.Use(handler.EnsureUser())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	mockRepo.On("EnsureUser", mock.Anything, &models.User{ID: "sub-1", Handle: "ivan", DisplayName: "Ivan"}).
+		Return(nil).Once()
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ping", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEnsureUser_UpdatesDisplayName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	name := "Ivan"
+	router.Use(func(c *gin.Context) {
+		auth.SetIdentity(c, auth.Identity{Subject: "sub-1", Name: name, Username: "ivan"})
+		c.Next()
+	})
+	router.Use(handler.EnsureUser())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	mockRepo.On("EnsureUser", mock.Anything, &models.User{ID: "sub-1", Handle: "ivan", DisplayName: "Ivan"}).
+		Return(nil).Once()
+	mockRepo.On("EnsureUser", mock.Anything, &models.User{ID: "sub-1", Handle: "ivan", DisplayName: "Ivan Petrov"}).
+		Return(nil).Once()
+
+	for _, name = range []string{"Ivan", "Ivan", "Ivan Petrov", "Ivan Petrov"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ping", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEnsureUser_FailureIsNotCached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.Use(func(c *gin.Context) {
+		auth.SetIdentity(c, auth.Identity{Subject: "sub-1"})
+		c.Next()
+	})
+	router.Use(handler.EnsureUser())
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	mockRepo.On("EnsureUser", mock.Anything, mock.Anything).Return(repository.ErrConflict).Twice()
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/ping", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUserQuestions_FiltersByAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/users/:id/questions", handler.GetUserQuestions)
+
+	mockRepo.On("GetUser", mock.Anything, "sub-1").Return(&models.User{ID: "sub-1"}, nil)
+	mockRepo.On("GetQuestions", mock.Anything, mock.MatchedBy(func(opts repository.QuestionListOptions) bool {
+		return opts.AuthorID == "sub-1"
+	})).Return(&repository.QuestionPage{Items: []models.Question{}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/sub-1/questions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUser_NotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockRepo := new(MockRepository)
+	handler := NewHandler(mockRepo)
+
+	router.GET("/users/:id", handler.GetUser)
+
+	mockRepo.On("GetUser", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/missing", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AcceptAnswer(c *gin.Context) {
+	h.setAcceptedAnswer(c, true)
+}
+
+func (h *Handler) UnacceptAnswer(c *gin.Context) {
+	h.setAcceptedAnswer(c, false)
+}
+
+// setAcceptedAnswer serves both accept and un-accept. Only the question
+// author may change the accepted answer.
+func (h *Handler) setAcceptedAnswer(c *gin.Context, accept bool) {
+	ctx := c.Request.Context()
+
+	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	answerID, err := strconv.ParseUint(c.Param("answer_id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("answer_id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	question, err := h.repo.GetQuestion(ctx, uint(questionID), repository.QuestionDetailOptions{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get question", "question_id", questionID, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to get question")
+		return
+	}
+
+	if question.AuthorID == nil || *question.AuthorID != userID {
+		slog.WarnContext(ctx, "Only the author can accept answers", "question_id", questionID, "user_id", userID)
+		respondError(c, http.StatusForbidden, "Only the question author can accept answers")
+		return
+	}
+
+	slog.InfoContext(ctx, "Changing accepted answer", "question_id", questionID, "answer_id", answerID, "accept", accept)
+
+	updated, err := h.repo.SetAcceptedAnswer(ctx, uint(questionID), uint(answerID), accept, parseIfMatch(c))
+	if errors.Is(err, repository.ErrNotAccepted) {
+		respondError(c, http.StatusNotFound, "Answer is not accepted")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to change accepted answer", "question_id", questionID, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to change accepted answer")
+		return
+	}
+
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
+}
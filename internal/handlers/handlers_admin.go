@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type SetRoleRequest struct {
+	Role models.Role `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// GrantRole sets the role of a user. Admins can't change their own role, so
+// the last admin can't lock everyone out.
+func (h *Handler) GrantRole(c *gin.Context) {
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Invalid request body", "error", err)
+		respondBindError(c, err)
+		return
+	}
+
+	h.setRole(c, req.Role)
+}
+
+// RevokeRole demotes a user back to the plain user role.
+func (h *Handler) RevokeRole(c *gin.Context) {
+	h.setRole(c, models.RoleUser)
+}
+
+func (h *Handler) setRole(c *gin.Context, role models.Role) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	if callerID, _ := auth.UserID(c); callerID == id {
+		respondError(c, http.StatusConflict, "You cannot change your own role")
+		return
+	}
+
+	slog.InfoContext(ctx, "Changing user role", "user_id", id, "role", role)
+
+	user, err := h.repo.SetUserRole(ctx, id, role)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to change user role", "user_id", id, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to change user role")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// GetAuditEvents lists audit events, newest first. All filters are optional
+// and combined with AND; from/to bound created_at as RFC 3339 timestamps.
+func (h *Handler) GetAuditEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	opts := repository.AuditListOptions{
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Limit:      repository.DefaultPageLimit,
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			respondError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxPageLimit))
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondError(c, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		opts.Offset = offset
+	}
+	for param, dst := range map[string]**time.Time{"from": &opts.From, "to": &opts.To} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, param+" must be an RFC 3339 timestamp")
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	slog.InfoContext(ctx, "Getting audit events", "limit", opts.Limit, "offset", opts.Offset)
+
+	page, err := h.repo.GetAuditEvents(ctx, opts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch audit events", "error", err)
+		respondRepositoryError(c, err, "Audit event not found", "Failed to fetch audit events")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
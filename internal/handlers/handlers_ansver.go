@@ -5,13 +5,19 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/metrics"
 	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
 type CreateAnswerRequest struct {
-	UserID string `json:"user_id" binding:"required,min=1"`
-	Text   string `json:"text" binding:"required,min=1"`
+	Text string `json:"text" binding:"required,min=1"`
+}
+
+type UpdateAnswerRequest struct {
+	Text string `json:"text" binding:"required,min=1"`
 }
 
 func (h *Handler) CreateAnswer(c *gin.Context) {
@@ -20,7 +26,7 @@ func (h *Handler) CreateAnswer(c *gin.Context) {
 	questionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
 		return
 	}
 
@@ -29,36 +35,45 @@ func (h *Handler) CreateAnswer(c *gin.Context) {
 	exists, err := h.repo.QuestionExists(ctx, uint(questionID))
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to check question existence", "question_id", questionID, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		respondRepositoryError(c, err, "Question not found", "Database error")
 		return
 	}
 
 	if !exists {
 		slog.WarnContext(ctx, "Question not found", "question_id", questionID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		respondError(c, http.StatusNotFound, "Question not found")
 		return
 	}
 
 	var req CreateAnswerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		slog.ErrorContext(ctx, "Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondBindError(c, err)
+		return
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	answer := models.Answer{
 		QuestionID: uint(questionID),
-		UserID:     req.UserID,
+		UserID:     userID,
 		Text:       req.Text,
 	}
 
-	err = h.repo.CreateAnswer(ctx, &answer)
+	err = h.repo.CreateAnswer(ctx, &answer, parseIfMatch(c))
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to create answer", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create answer"})
+		respondRepositoryError(c, err, "Question not found", "Failed to create answer")
 		return
 	}
 
+	metrics.AnswersCreated.Inc()
+
+	setETag(c, answer.Version)
 	c.JSON(http.StatusCreated, answer)
 }
 
@@ -68,7 +83,7 @@ func (h *Handler) GetAnswer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid answer ID"})
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
 		return
 	}
 
@@ -76,11 +91,71 @@ func (h *Handler) GetAnswer(c *gin.Context) {
 
 	answer, err := h.repo.GetAnswer(ctx, uint(id))
 	if err != nil {
-		slog.ErrorContext(ctx, "Answer not found", "answer_id", id, "error", err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+		slog.ErrorContext(ctx, "Failed to get answer", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to get answer")
+		return
+	}
+
+	etag := formatETag(answer.Version, "")
+	c.Header("ETag", etag)
+	if notModified(c, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, answer)
+}
+
+func (h *Handler) UpdateAnswer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	var req UpdateAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.ErrorContext(ctx, "Invalid request body", "error", err)
+		respondBindError(c, err)
+		return
+	}
+
+	editor, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ifMatch := parseIfMatch(c)
+
+	current, err := h.repo.GetAnswer(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get answer", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to update answer")
+		return
+	}
+
+	if !h.canManage(c, &current.UserID) {
 		return
 	}
 
+	slog.InfoContext(ctx, "Updating answer", "answer_id", id, "editor", editor)
+
+	answer, err := h.repo.UpdateAnswer(ctx, uint(id), repository.TextUpdate{
+		Text:    req.Text,
+		Editor:  editor,
+		IfMatch: ifMatch,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update answer", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to update answer")
+		return
+	}
+
+	setETag(c, answer.Version)
 	c.JSON(http.StatusOK, answer)
 }
 
@@ -90,16 +165,29 @@ func (h *Handler) DeleteAnswer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid answer ID"})
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	ifMatch := parseIfMatch(c)
+
+	answer, err := h.repo.GetAnswer(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get answer", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to delete answer")
+		return
+	}
+
+	if !h.canManage(c, &answer.UserID) {
 		return
 	}
 
 	slog.InfoContext(ctx, "Deleting answer", "answer_id", id)
 
-	err = h.repo.DeleteAnswer(ctx, uint(id))
+	err = h.repo.DeleteAnswer(ctx, uint(id), ifMatch)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to delete answer", "answer_id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete answer"})
+		respondRepositoryError(c, err, "Answer not found", "Failed to delete answer")
 		return
 	}
 
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateCommentRequest struct {
+	Text string `json:"text" binding:"required,min=1,max=600"`
+}
+
+func (h *Handler) CreateQuestionComment(c *gin.Context) {
+	h.createComment(c, "question")
+}
+
+func (h *Handler) CreateAnswerComment(c *gin.Context) {
+	h.createComment(c, "answer")
+}
+
+// createComment serves both comment endpoints; target names the entity the
+// :id parameter refers to.
+func (h *Handler) createComment(c *gin.Context, target string) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid "+target+" ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid "+target+" ID")
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.ErrorContext(ctx, "Invalid request body", "error", err)
+		respondBindError(c, err)
+		return
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	parentID := uint(id)
+	comment := models.Comment{UserID: userID, Text: req.Text}
+	if target == "question" {
+		comment.QuestionID = &parentID
+	} else {
+		comment.AnswerID = &parentID
+	}
+
+	slog.InfoContext(ctx, "Creating comment", target+"_id", id)
+
+	if err := h.repo.CreateComment(ctx, &comment, parseIfMatch(c)); err != nil {
+		slog.ErrorContext(ctx, "Failed to create comment", target+"_id", id, "error", err)
+		respondRepositoryError(c, err, "Comment target not found", "Failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+func (h *Handler) GetQuestionComments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	comments, err := h.repo.GetQuestionComments(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get question comments", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to get comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *Handler) GetAnswerComments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	comments, err := h.repo.GetAnswerComments(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get answer comments", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to get comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// DeleteComment removes a comment. Authors delete their own comments,
+// moderators delete any.
+func (h *Handler) DeleteComment(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid comment ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	comment, err := h.repo.GetComment(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get comment", "comment_id", id, "error", err)
+		respondRepositoryError(c, err, "Comment not found", "Failed to delete comment")
+		return
+	}
+
+	if !h.canManage(c, &comment.UserID) {
+		return
+	}
+
+	slog.InfoContext(ctx, "Deleting comment", "comment_id", id)
+
+	if err := h.repo.DeleteComment(ctx, uint(id)); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete comment", "comment_id", id, "error", err)
+		respondRepositoryError(c, err, "Comment not found", "Failed to delete comment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,31 +1,130 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/metrics"
 	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
 type CreateQuestionRequest struct {
+	Text string   `json:"text" binding:"required,min=1"`
+	Tags []string `json:"tags"`
+}
+
+type UpdateQuestionRequest struct {
 	Text string `json:"text" binding:"required,min=1"`
 }
 
 func (h *Handler) GetQuestions(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	slog.InfoContext(ctx, "Getting all questions")
+	opts, err := parseQuestionListOptions(c)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid query parameters", "error", err)
+		respondError(c, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	slog.InfoContext(ctx, "Getting questions", "limit", opts.Limit, "offset", opts.Offset, "sort", opts.Sort)
 
-	questions, err := h.repo.GetQuestions(ctx)
+	page, err := h.repo.GetQuestions(ctx, opts)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to fetch questions", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch questions"})
+		respondRepositoryError(c, err, "Question not found", "Failed to fetch questions")
 		return
 	}
 
-	c.JSON(http.StatusOK, questions)
+	c.JSON(http.StatusOK, page)
+}
+
+func parseQuestionListOptions(c *gin.Context) (repository.QuestionListOptions, error) {
+	opts := repository.QuestionListOptions{
+		Limit:  repository.DefaultPageLimit,
+		Cursor: c.Query("cursor"),
+		Sort:   repository.SortByCreatedAt,
+		Desc:   true,
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			return opts, fmt.Errorf("limit must be between 1 and %d", repository.MaxPageLimit)
+		}
+		opts.Limit = limit
+	}
+
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return opts, errors.New("offset must be a non-negative integer")
+		}
+		opts.Offset = offset
+	}
+
+	for param, dst := range map[string]**time.Time{
+		"created_after":  &opts.CreatedAfter,
+		"created_before": &opts.CreatedBefore,
+	} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return opts, fmt.Errorf("%s must be an RFC 3339 timestamp", param)
+			}
+			*dst = &t
+		}
+	}
+
+	if v := c.Query("answered"); v != "" {
+		answered, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, errors.New("answered must be true or false")
+		}
+		opts.Answered = &answered
+	}
+
+	if raw := c.QueryArray("tag"); len(raw) > 0 {
+		tags, err := normalizeTags(raw)
+		if err != nil {
+			return opts, err
+		}
+		opts.Tags = tags
+	}
+
+	switch mode := repository.TagMode(c.Query("tag_mode")); mode {
+	case "":
+	case repository.TagModeAll, repository.TagModeAny:
+		opts.TagMode = mode
+	default:
+		return opts, errors.New("tag_mode must be all or any")
+	}
+
+	switch sort := repository.QuestionSort(c.Query("sort")); sort {
+	case "":
+	case repository.SortByCreatedAt, repository.SortByAnswerCount:
+		opts.Sort = sort
+	default:
+		return opts, errors.New("sort must be one of: created_at, answers")
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+	case "asc":
+		opts.Desc = false
+	default:
+		return opts, errors.New("order must be asc or desc")
+	}
+
+	return opts, nil
 }
 
 func (h *Handler) CreateQuestion(c *gin.Context) {
@@ -36,21 +135,45 @@ func (h *Handler) CreateQuestion(c *gin.Context) {
 	var req CreateQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		slog.ErrorContext(ctx, "Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondBindError(c, err)
+		return
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err == nil && len(tags) > maxTagsPerQuestion {
+		err = fmt.Errorf("at most %d tags are allowed", maxTagsPerQuestion)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid tags", "error", err)
+		respondError(c, http.StatusBadRequest, "Invalid tags: "+err.Error())
 		return
 	}
 
 	question := models.Question{
-		Text: req.Text,
+		AuthorID: &userID,
+		Text:     req.Text,
+		Tags:     make([]models.Tag, 0, len(tags)),
+	}
+	for _, name := range tags {
+		question.Tags = append(question.Tags, models.Tag{Name: name})
 	}
 
-	err := h.repo.CreateQuestion(ctx, &question)
+	err = h.repo.CreateQuestion(ctx, &question)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to create question", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create question"})
+		respondRepositoryError(c, err, "Question not found", "Failed to create question")
 		return
 	}
 
+	metrics.QuestionsCreated.Inc()
+
+	setETag(c, question.Version)
 	c.JSON(http.StatusCreated, question)
 }
 
@@ -60,38 +183,144 @@ func (h *Handler) GetQuestion(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
 		return
 	}
 
-	slog.InfoContext(ctx, "Getting question with answers", "question_id", id)
+	opts := repository.QuestionDetailOptions{AnswerSort: repository.SortAnswersByOldest}
+	switch sort := repository.AnswerSort(c.Query("sort")); sort {
+	case "":
+	case repository.SortAnswersByScore, repository.SortAnswersByNewest, repository.SortAnswersByOldest:
+		opts.AnswerSort = sort
+	default:
+		respondError(c, http.StatusBadRequest, "sort must be one of: score, newest, oldest")
+		return
+	}
 
-	question, err := h.repo.GetQuestion(ctx, uint(id))
+	switch include := c.Query("include"); include {
+	case "":
+	case "comments":
+		opts.IncludeComments = true
+	default:
+		respondError(c, http.StatusBadRequest, "include must be comments")
+		return
+	}
+
+	slog.InfoContext(ctx, "Getting question with answers", "question_id", id, "sort", opts.AnswerSort)
+
+	question, err := h.repo.GetQuestion(ctx, uint(id), opts)
 	if err != nil {
-		slog.ErrorContext(ctx, "Question not found", "question_id", id, "error", err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+		slog.ErrorContext(ctx, "Failed to get question", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to get question")
+		return
+	}
+
+	etag := formatETag(question.Version, questionVariant(opts))
+	c.Header("ETag", etag)
+	if notModified(c, etag) {
+		c.Status(http.StatusNotModified)
 		return
 	}
 
 	c.JSON(http.StatusOK, question)
 }
 
+// questionVariant names the representation GetQuestion returns for opts, so
+// each combination of sort and include gets its own ETag. The default
+// representation has none.
+func questionVariant(opts repository.QuestionDetailOptions) string {
+	var parts []string
+	if opts.AnswerSort != repository.SortAnswersByOldest {
+		parts = append(parts, string(opts.AnswerSort))
+	}
+	if opts.IncludeComments {
+		parts = append(parts, "comments")
+	}
+	return strings.Join(parts, "-")
+}
+
+func (h *Handler) UpdateQuestion(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	var req UpdateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.ErrorContext(ctx, "Invalid request body", "error", err)
+		respondBindError(c, err)
+		return
+	}
+
+	editor, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ifMatch := parseIfMatch(c)
+
+	current, err := h.repo.GetQuestion(ctx, uint(id), repository.QuestionDetailOptions{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get question", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to update question")
+		return
+	}
+
+	if !h.canManage(c, current.AuthorID) {
+		return
+	}
+
+	slog.InfoContext(ctx, "Updating question", "question_id", id, "editor", editor)
+
+	question, err := h.repo.UpdateQuestion(ctx, uint(id), repository.TextUpdate{
+		Text:    req.Text,
+		Editor:  editor,
+		IfMatch: ifMatch,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update question", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to update question")
+		return
+	}
+
+	setETag(c, question.Version)
+	c.JSON(http.StatusOK, question)
+}
+
 func (h *Handler) DeleteQuestion(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	ifMatch := parseIfMatch(c)
+
+	question, err := h.repo.GetQuestion(ctx, uint(id), repository.QuestionDetailOptions{})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get question", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to delete question")
+		return
+	}
+
+	if !h.canManage(c, question.AuthorID) {
 		return
 	}
 
 	slog.InfoContext(ctx, "Deleting question", "question_id", id)
 
-	err = h.repo.DeleteQuestion(ctx, uint(id))
+	err = h.repo.DeleteQuestion(ctx, uint(id), ifMatch)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to delete question", "question_id", id, "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete question"})
+		respondRepositoryError(c, err, "Question not found", "Failed to delete question")
 		return
 	}
 
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NKV510/question-answer-api/internal/diff"
+	"github.com/gin-gonic/gin"
+)
+
+// currentRevision refers to the live text in the diff endpoint.
+const currentRevision = "current"
+
+type RevisionDiffResponse struct {
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Changes []diff.Op `json:"changes"`
+}
+
+func (h *Handler) GetQuestionRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	exists, err := h.repo.QuestionExists(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to check question existence", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Database error")
+		return
+	}
+	if !exists {
+		respondError(c, http.StatusNotFound, "Question not found")
+		return
+	}
+
+	revisions, err := h.repo.GetQuestionRevisions(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch question revisions", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to fetch revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DiffQuestionRevisions compares the text of two revisions. A revision holds
+// the text as it was before that edit; to=current compares with the live text.
+func (h *Handler) DiffQuestionRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	h.diffRevisions(c, func(ref string) (string, error) {
+		return h.questionRevisionText(ctx, uint(id), ref)
+	})
+}
+
+// DiffAnswerRevisions is DiffQuestionRevisions for answers.
+func (h *Handler) DiffAnswerRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	h.diffRevisions(c, func(ref string) (string, error) {
+		return h.answerRevisionText(ctx, uint(id), ref)
+	})
+}
+
+// diffRevisions responds with the diff between the from and to revisions,
+// resolving each with text.
+func (h *Handler) diffRevisions(c *gin.Context, text func(ref string) (string, error)) {
+	from := c.Query("from")
+	to := c.DefaultQuery("to", currentRevision)
+	if from == "" {
+		respondError(c, http.StatusBadRequest, "Query parameter from is required")
+		return
+	}
+
+	fromText, err := text(from)
+	if err != nil {
+		h.respondRevisionError(c, err)
+		return
+	}
+	toText, err := text(to)
+	if err != nil {
+		h.respondRevisionError(c, err)
+		return
+	}
+
+	changes, err := diff.Lines(fromText, toText)
+	if errors.Is(err, diff.ErrTooLarge) {
+		respondError(c, http.StatusUnprocessableEntity, "Revisions differ in too many lines to diff")
+		return
+	}
+	if changes == nil {
+		changes = []diff.Op{}
+	}
+
+	c.JSON(http.StatusOK, RevisionDiffResponse{From: from, To: to, Changes: changes})
+}
+
+var errInvalidRevision = errors.New("invalid revision")
+
+func (h *Handler) questionRevisionText(ctx context.Context, questionID uint, ref string) (string, error) {
+	if ref == currentRevision {
+		return h.repo.GetQuestionText(ctx, questionID)
+	}
+
+	revisionID, err := strconv.ParseUint(ref, 10, 32)
+	if err != nil {
+		return "", errInvalidRevision
+	}
+
+	revision, err := h.repo.GetQuestionRevision(ctx, questionID, uint(revisionID))
+	if err != nil {
+		return "", err
+	}
+	return revision.PreviousText, nil
+}
+
+func (h *Handler) answerRevisionText(ctx context.Context, answerID uint, ref string) (string, error) {
+	if ref == currentRevision {
+		answer, err := h.repo.GetAnswer(ctx, answerID)
+		if err != nil {
+			return "", err
+		}
+		return answer.Text, nil
+	}
+
+	revisionID, err := strconv.ParseUint(ref, 10, 32)
+	if err != nil {
+		return "", errInvalidRevision
+	}
+
+	revision, err := h.repo.GetAnswerRevision(ctx, answerID, uint(revisionID))
+	if err != nil {
+		return "", err
+	}
+	return revision.PreviousText, nil
+}
+
+func (h *Handler) respondRevisionError(c *gin.Context, err error) {
+	if errors.Is(err, errInvalidRevision) {
+		respondError(c, http.StatusBadRequest, "Revision must be a revision ID or \"current\"")
+		return
+	}
+	slog.ErrorContext(c.Request.Context(), "Failed to load revision", "error", err)
+	respondRepositoryError(c, err, "Revision not found", "Failed to load revision")
+}
+
+func (h *Handler) GetAnswerRevisions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	if _, err := h.repo.GetAnswer(ctx, uint(id)); err != nil {
+		slog.ErrorContext(ctx, "Failed to get answer", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to get answer")
+		return
+	}
+
+	revisions, err := h.repo.GetAnswerRevisions(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch answer revisions", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to fetch revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
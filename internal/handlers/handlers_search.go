@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const maxSearchQueryLength = 256
+
+func (h *Handler) Search(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || len(query) > maxSearchQueryLength {
+		slog.WarnContext(ctx, "Invalid search query", "length", len(query))
+		respondError(c, http.StatusBadRequest, "Query parameter q is required and must be at most 256 bytes")
+		return
+	}
+
+	opts := repository.SearchOptions{
+		Query: query,
+		Limit: repository.DefaultPageLimit,
+	}
+
+	switch t := repository.SearchType(c.Query("type")); t {
+	case repository.SearchAll, repository.SearchQuestions, repository.SearchAnswers:
+		opts.Type = t
+	default:
+		respondError(c, http.StatusBadRequest, "type must be question or answer")
+		return
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			respondError(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		opts.Limit = limit
+	}
+
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondError(c, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+		opts.Offset = offset
+	}
+
+	slog.InfoContext(ctx, "Searching", "type", opts.Type, "limit", opts.Limit, "offset", opts.Offset)
+
+	result, err := h.repo.Search(ctx, opts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to search", "error", err)
+		respondRepositoryError(c, err, "Nothing found", "Failed to search")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
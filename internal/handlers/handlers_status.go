@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ChangeStatusRequest struct {
+	Status      models.QuestionStatus `json:"status" binding:"required,oneof=open closed locked duplicate"`
+	Reason      string                `json:"reason" binding:"max=500"`
+	DuplicateOf *uint                 `json:"duplicate_of"`
+}
+
+// ChangeQuestionStatus moves a question through its lifecycle. Closing
+// requires a reason and marking as duplicate requires duplicate_of. The route
+// is restricted to moderators.
+func (h *Handler) ChangeQuestionStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	var req ChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.ErrorContext(ctx, "Invalid request body", "error", err)
+		respondBindError(c, err)
+		return
+	}
+
+	switch {
+	case req.Status == models.QuestionClosed && req.Reason == "":
+		respondError(c, http.StatusBadRequest, "reason is required to close a question")
+		return
+	case req.Status == models.QuestionDuplicate && req.DuplicateOf == nil:
+		respondError(c, http.StatusBadRequest, "duplicate_of is required for duplicate status")
+		return
+	case req.Status != models.QuestionDuplicate && req.DuplicateOf != nil:
+		respondError(c, http.StatusBadRequest, "duplicate_of is only allowed for duplicate status")
+		return
+	case req.DuplicateOf != nil && *req.DuplicateOf == uint(id):
+		respondError(c, http.StatusBadRequest, "A question cannot be a duplicate of itself")
+		return
+	}
+
+	ifMatch := parseIfMatch(c)
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if req.DuplicateOf != nil {
+		exists, err := h.repo.QuestionExists(ctx, *req.DuplicateOf)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to check question existence", "question_id", *req.DuplicateOf, "error", err)
+			respondRepositoryError(c, err, "Question not found", "Database error")
+			return
+		}
+		if !exists {
+			respondError(c, http.StatusUnprocessableEntity, "duplicate_of question not found")
+			return
+		}
+	}
+
+	slog.InfoContext(ctx, "Changing question status", "question_id", id, "status", req.Status)
+
+	updated, err := h.repo.ChangeQuestionStatus(ctx, uint(id), repository.StatusChange{
+		Status:      req.Status,
+		Reason:      req.Reason,
+		DuplicateOf: req.DuplicateOf,
+		ChangedBy:   userID,
+		IfMatch:     ifMatch,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to change question status", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to change question status")
+		return
+	}
+
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) GetQuestionStatusHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	exists, err := h.repo.QuestionExists(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to check question existence", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Database error")
+		return
+	}
+	if !exists {
+		respondError(c, http.StatusNotFound, "Question not found")
+		return
+	}
+
+	changes, err := h.repo.GetQuestionStatusHistory(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch question status history", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to fetch status history")
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxTagLength       = 32
+	maxTagsPerQuestion = 5
+)
+
+// normalizeTags lowercases and trims tag names, drops duplicates and checks
+// the length limit and the allowed characters (letters, digits and "+#.-_").
+func normalizeTags(raw []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(raw))
+	tags := make([]string, 0, len(raw))
+
+	for _, name := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return nil, errors.New("tag must not be empty")
+		}
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", name, maxTagLength)
+		}
+		for _, r := range name {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+#.-_", r) {
+				return nil, fmt.Errorf("tag %q contains invalid character %q", name, r)
+			}
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		tags = append(tags, name)
+	}
+
+	return tags, nil
+}
+
+func (h *Handler) GetTags(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	opts := repository.TagListOptions{Limit: repository.DefaultPageLimit}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			respondError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxPageLimit))
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondError(c, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		opts.Offset = offset
+	}
+
+	slog.InfoContext(ctx, "Getting tags", "limit", opts.Limit, "offset", opts.Offset)
+
+	page, err := h.repo.GetTags(ctx, opts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch tags", "error", err)
+		respondRepositoryError(c, err, "Tag not found", "Failed to fetch tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
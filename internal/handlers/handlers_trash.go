@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash lists deleted questions: the caller's own for users, all of them
+// for moderators.
+func (h *Handler) GetTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	role, err := h.callerRole(c)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load user role", "user_id", userID, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to fetch trash")
+		return
+	}
+
+	opts := repository.TrashListOptions{AuthorID: userID, Limit: repository.DefaultPageLimit}
+	if role.AtLeast(models.RoleModerator) {
+		opts.AuthorID = ""
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			respondError(c, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxPageLimit))
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondError(c, http.StatusBadRequest, "offset must be a non-negative integer")
+			return
+		}
+		opts.Offset = offset
+	}
+
+	slog.InfoContext(ctx, "Getting trash", "limit", opts.Limit, "offset", opts.Offset)
+
+	page, err := h.repo.GetTrash(ctx, opts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch trash", "error", err)
+		respondRepositoryError(c, err, "Question not found", "Failed to fetch trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// RestoreQuestion takes a deleted question out of the trash. Authors restore
+// their own questions, moderators restore any.
+func (h *Handler) RestoreQuestion(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid question ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	deleted, err := h.repo.GetDeletedQuestion(ctx, uint(id))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get deleted question", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found in trash", "Failed to restore question")
+		return
+	}
+
+	if !h.canManage(c, deleted.AuthorID) {
+		return
+	}
+
+	slog.InfoContext(ctx, "Restoring question", "question_id", id)
+
+	question, err := h.repo.RestoreQuestion(ctx, uint(id), parseIfMatch(c))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to restore question", "question_id", id, "error", err)
+		respondRepositoryError(c, err, "Question not found in trash", "Failed to restore question")
+		return
+	}
+
+	setETag(c, question.Version)
+	c.JSON(http.StatusOK, question)
+}
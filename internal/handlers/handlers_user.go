@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// EnsureUser creates the users row for an authenticated caller the first time
+// they are seen, so authored content always references an existing user, and
+// keeps the display name in step with the token. Anonymous requests pass
+// through untouched.
+func (h *Handler) EnsureUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := auth.CurrentIdentity(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		user := models.User{
+			ID:          identity.Subject,
+			Handle:      identity.Subject,
+			DisplayName: identity.Subject,
+		}
+		if identity.Username != "" {
+			user.Handle = identity.Username
+			user.DisplayName = identity.Username
+		}
+		if identity.Name != "" {
+			user.DisplayName = identity.Name
+		}
+
+		if h.knownUsers.fresh(user.ID, user.DisplayName, time.Now()) {
+			c.Next()
+			return
+		}
+
+		if err := h.repo.EnsureUser(ctx, &user); err != nil {
+			slog.ErrorContext(ctx, "Failed to register user", "user_id", user.ID, "error", err)
+			respondRepositoryError(c, err, "User not found", "Failed to register user")
+			c.Abort()
+			return
+		}
+
+		h.knownUsers.add(user.ID, user.DisplayName, time.Now())
+		c.Next()
+	}
+}
+
+func (h *Handler) GetUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	slog.InfoContext(ctx, "Getting user", "user_id", id)
+
+	user, err := h.repo.GetUser(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get user", "user_id", id, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) GetUserAnswers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	opts := repository.AnswerListOptions{Limit: repository.DefaultPageLimit}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			respondError(c, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		opts.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondError(c, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+		opts.Offset = offset
+	}
+
+	slog.InfoContext(ctx, "Getting user answers", "user_id", id)
+
+	if _, err := h.repo.GetUser(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "Failed to get user", "user_id", id, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to get user")
+		return
+	}
+
+	page, err := h.repo.GetUserAnswers(ctx, id, opts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch user answers", "user_id", id, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to fetch answers")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetUserQuestions accepts the same query parameters as GET /questions.
+func (h *Handler) GetUserQuestions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Param("id")
+
+	opts, err := parseQuestionListOptions(c)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid query parameters", "error", err)
+		respondError(c, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+	opts.AuthorID = id
+
+	slog.InfoContext(ctx, "Getting user questions", "user_id", id)
+
+	if _, err := h.repo.GetUser(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "Failed to get user", "user_id", id, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to get user")
+		return
+	}
+
+	page, err := h.repo.GetQuestions(ctx, opts)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to fetch user questions", "user_id", id, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to fetch questions")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+type VoteRequest struct {
+	Value int `json:"value" binding:"required,oneof=1 -1"`
+}
+
+func (h *Handler) VoteAnswer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	var req VoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.ErrorContext(ctx, "Invalid request body", "error", err)
+		respondBindError(c, err)
+		return
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	slog.InfoContext(ctx, "Voting on answer", "answer_id", id, "value", req.Value)
+
+	answer, err := h.repo.VoteAnswer(ctx, uint(id), userID, req.Value, parseIfMatch(c))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to vote on answer", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Answer not found", "Failed to vote")
+		return
+	}
+
+	setETag(c, answer.Version)
+	c.JSON(http.StatusOK, answer)
+}
+
+func (h *Handler) RetractVote(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		slog.ErrorContext(ctx, "Invalid answer ID", "error", err, "id", c.Param("id"))
+		respondError(c, http.StatusBadRequest, "Invalid answer ID")
+		return
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	slog.InfoContext(ctx, "Retracting vote", "answer_id", id)
+
+	answer, err := h.repo.RetractVote(ctx, uint(id), userID, parseIfMatch(c))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to retract vote", "answer_id", id, "error", err)
+		respondRepositoryError(c, err, "Vote not found", "Failed to retract vote")
+		return
+	}
+
+	setETag(c, answer.Version)
+	c.JSON(http.StatusOK, answer)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const roleContextKey = "handlers.role"
+
+// callerRole returns the role of the authenticated caller. It is read from
+// the users table once per request, so role changes apply immediately.
+// Anonymous callers and callers without a users row are plain users.
+func (h *Handler) callerRole(c *gin.Context) (models.Role, error) {
+	if v, ok := c.Get(roleContextKey); ok {
+		return v.(models.Role), nil
+	}
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		return models.RoleUser, nil
+	}
+
+	role := models.RoleUser
+	user, err := h.repo.GetUser(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+	case err != nil:
+		return "", err
+	case user.Role.Valid():
+		role = user.Role
+	}
+
+	c.Set(roleContextKey, role)
+	return role, nil
+}
+
+// RequireRole rejects callers whose role is below min with 403. It must run
+// after authentication; anonymous callers get 401.
+func (h *Handler) RequireRole(min models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		userID, ok := auth.UserID(c)
+		if !ok {
+			c.Header("WWW-Authenticate", "Bearer")
+			respondError(c, http.StatusUnauthorized, "Authentication required")
+			c.Abort()
+			return
+		}
+
+		role, err := h.callerRole(c)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load user role", "user_id", userID, "error", err)
+			respondRepositoryError(c, err, "User not found", "Failed to check permissions")
+			c.Abort()
+			return
+		}
+
+		if !role.AtLeast(min) {
+			slog.WarnContext(ctx, "Insufficient role", "user_id", userID, "role", role, "required", min)
+			respondError(c, http.StatusForbidden, "Insufficient permissions")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// canManage reports whether the caller may edit, remove or restore content
+// owned by authorID: authors manage their own content, moderators manage anything.
+// On false the error response has already been written.
+func (h *Handler) canManage(c *gin.Context, authorID *string) bool {
+	ctx := c.Request.Context()
+
+	userID, ok := auth.UserID(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, "Authentication required")
+		return false
+	}
+	if authorID != nil && *authorID == userID {
+		return true
+	}
+
+	role, err := h.callerRole(c)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to load user role", "user_id", userID, "error", err)
+		respondRepositoryError(c, err, "User not found", "Failed to check permissions")
+		return false
+	}
+	if role.AtLeast(models.RoleModerator) {
+		return true
+	}
+
+	slog.WarnContext(ctx, "Only the author or a moderator may do this", "user_id", userID)
+	respondError(c, http.StatusForbidden, "Only the author or a moderator can do this")
+	return false
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	userCacheSize = 10000
+	userCacheTTL  = 10 * time.Minute
+)
+
+// userCache remembers which users EnsureUser has stored, and under which
+// display name, so most requests skip the database. Entries expire after ttl
+// so a user whose row was removed is created again, and the least recently
+// used entry is evicted once size entries are held.
+type userCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+}
+
+type userCacheEntry struct {
+	id          string
+	displayName string
+	expires     time.Time
+}
+
+func newUserCache(size int, ttl time.Duration) *userCache {
+	return &userCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// fresh reports whether the user was stored with displayName less than ttl
+// before now.
+func (c *userCache) fresh(id, displayName string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[id]
+	if !ok {
+		return false
+	}
+	entry := el.Value.(*userCacheEntry)
+	if !now.Before(entry.expires) {
+		c.order.Remove(el)
+		delete(c.entries, id)
+		return false
+	}
+	c.order.MoveToFront(el)
+	return entry.displayName == displayName
+}
+
+func (c *userCache) add(id, displayName string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		entry := el.Value.(*userCacheEntry)
+		entry.displayName = displayName
+		entry.expires = now.Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[id] = c.order.PushFront(&userCacheEntry{id: id, displayName: displayName, expires: now.Add(c.ttl)})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*userCacheEntry).id)
+	}
+}
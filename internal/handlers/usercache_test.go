@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserCache(t *testing.T) {
+	now := time.Now()
+	cache := newUserCache(2, time.Minute)
+
+	assert.False(t, cache.fresh("a", "A", now))
+
+	cache.add("a", "A", now)
+	assert.True(t, cache.fresh("a", "A", now))
+	assert.False(t, cache.fresh("a", "Renamed", now), "a changed display name needs storing")
+	assert.False(t, cache.fresh("a", "A", now.Add(time.Minute)), "entries expire")
+
+	cache.add("a", "A", now)
+	cache.add("b", "B", now)
+	cache.fresh("a", "A", now)
+	cache.add("c", "C", now)
+	assert.True(t, cache.fresh("a", "A", now))
+	assert.False(t, cache.fresh("b", "B", now), "the least recently used entry is evicted")
+	assert.True(t, cache.fresh("c", "C", now))
+}
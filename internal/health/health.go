@@ -0,0 +1,177 @@
+// Package health serves the liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Version is the release version, set at build time with
+// -ldflags "-X github.com/NKV510/question-answer-api/internal/health.Version=v1.2.3".
+var Version = "dev"
+
+const (
+	LivePath  = "/livez"
+	ReadyPath = "/readyz"
+)
+
+type Build struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+type Check struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status           string           `json:"status"`
+	Checks           map[string]Check `json:"checks,omitempty"`
+	MigrationVersion *int64           `json:"migration_version,omitempty"`
+	Build            Build            `json:"build"`
+}
+
+// Checker reports whether the instance can serve traffic. Once Drain is
+// called it reports not ready, so load balancers stop routing to it while
+// in-flight requests finish.
+type Checker struct {
+	timeout  time.Duration
+	draining atomic.Bool
+	build    Build
+
+	ping             func(ctx context.Context) error
+	migrationVersion func(ctx context.Context) (int64, error)
+	checks           []namedCheck
+}
+
+type namedCheck struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// New returns a checker for db. Each readiness probe waits at most timeout
+// for the database.
+func New(db *gorm.DB, timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		build:   buildInfo(),
+		ping: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+		migrationVersion: func(ctx context.Context) (int64, error) {
+			var version int64
+			err := db.WithContext(ctx).
+				Raw("SELECT version_id FROM goose_db_version WHERE is_applied ORDER BY id DESC LIMIT 1").
+				Scan(&version).Error
+			return version, err
+		},
+	}
+}
+
+// AddCheck adds a readiness check reported under name. A failing check makes
+// /readyz return 503. Checks must be added before the server starts.
+func (h *Checker) AddCheck(name string, fn func(ctx context.Context) error) {
+	h.checks = append(h.checks, namedCheck{name: name, fn: fn})
+}
+
+// Drain marks the instance as shutting down.
+func (h *Checker) Drain() {
+	h.draining.Store(true)
+}
+
+// Live reports that the process is running. It doesn't touch dependencies, so
+// a database outage doesn't get the instance restarted.
+func (h *Checker) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready pings the database, runs the added checks and reports the applied
+// migration version and build info. It returns 503 while draining or when any
+// check fails.
+func (h *Checker) Ready(c *gin.Context) {
+	report := Report{Status: "ok", Build: h.build}
+
+	if h.draining.Load() {
+		report.Status = "draining"
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	check := Check{Status: "ok"}
+	if err := h.ping(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("ping timed out after " + h.timeout.String())
+		}
+		slog.WarnContext(ctx, "Readiness check failed", "check", "database", "error", err)
+		check = Check{Status: "fail", Error: err.Error()}
+		report.Status = "fail"
+	}
+	check.Latency = time.Since(start).String()
+	report.Checks = map[string]Check{"database": check}
+
+	for _, nc := range h.checks {
+		check := Check{Status: "ok"}
+		if err := nc.fn(ctx); err != nil {
+			slog.WarnContext(ctx, "Readiness check failed", "check", nc.name, "error", err)
+			check = Check{Status: "fail", Error: err.Error()}
+			report.Status = "fail"
+		}
+		report.Checks[nc.name] = check
+	}
+
+	if report.Status == "ok" {
+		if version, err := h.migrationVersion(ctx); err != nil {
+			slog.WarnContext(ctx, "Failed to read migration version", "error", err)
+		} else {
+			report.MigrationVersion = &version
+		}
+	}
+
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+func buildInfo() Build {
+	build := Build{Version: Version}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	build.GoVersion = info.GoVersion
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			build.Revision = s.Value
+		case "vcs.time":
+			build.Time = s.Value
+		case "vcs.modified":
+			build.Modified = s.Value == "true"
+		}
+	}
+	return build
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChecker(ping func(context.Context) error) *Checker {
+	return &Checker{
+		timeout:          50 * time.Millisecond,
+		build:            Build{Version: "test"},
+		ping:             ping,
+		migrationVersion: func(context.Context) (int64, error) { return 20260105090000, nil },
+	}
+}
+
+func serve(h *Checker, path string) (*httptest.ResponseRecorder, Report) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(LivePath, h.Live)
+	router.GET(ReadyPath, h.Ready)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+
+	var report Report
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	return w, report
+}
+
+func TestReady(t *testing.T) {
+	h := newTestChecker(func(context.Context) error { return nil })
+
+	w, report := serve(h, ReadyPath)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", report.Status)
+	assert.Equal(t, "ok", report.Checks["database"].Status)
+	require.NotNil(t, report.MigrationVersion)
+	assert.Equal(t, int64(20260105090000), *report.MigrationVersion)
+	assert.Equal(t, "test", report.Build.Version)
+}
+
+func TestReady_DatabaseDown(t *testing.T) {
+	h := newTestChecker(func(context.Context) error { return errors.New("connection refused") })
+
+	w, report := serve(h, ReadyPath)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "fail", report.Status)
+	assert.Equal(t, "connection refused", report.Checks["database"].Error)
+	assert.Nil(t, report.MigrationVersion)
+}
+
+func TestReady_AddedCheckFails(t *testing.T) {
+	h := newTestChecker(func(context.Context) error { return nil })
+	h.AddCheck("schema", func(context.Context) error { return errors.New("2 differences") })
+
+	w, report := serve(h, ReadyPath)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "fail", report.Status)
+	assert.Equal(t, "ok", report.Checks["database"].Status)
+	assert.Equal(t, "2 differences", report.Checks["schema"].Error)
+}
+
+func TestReady_Timeout(t *testing.T) {
+	h := newTestChecker(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	w, report := serve(h, ReadyPath)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, report.Checks["database"].Error, "timed out")
+}
+
+func TestReady_Draining(t *testing.T) {
+	pinged := false
+	h := newTestChecker(func(context.Context) error {
+		pinged = true
+		return nil
+	})
+	h.Drain()
+
+	w, report := serve(h, ReadyPath)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "draining", report.Status)
+	assert.False(t, pinged)
+
+	// Liveness is unaffected: the process is still healthy.
+	w, _ = serve(h, LivePath)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,213 @@
+// Package idempotency makes POST requests safe to retry. The first response
+// for an Idempotency-Key is stored and replayed for later requests with the
+// same key, caller and payload.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with the body.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// LockTimeout is how long a reservation may stay in progress. After that the
+// request that made it is assumed to have died, and a retry takes it over.
+const LockTimeout = time.Minute
+
+// ErrInProgress is returned by Store.Reserve when another request holds the
+// same key and has not finished yet.
+var ErrInProgress = errors.New("request with this idempotency key is in progress")
+
+// ErrReservationLost is returned by Store.Complete when the reservation was
+// taken over by another request after LockTimeout.
+var ErrReservationLost = errors.New("idempotency key reservation was taken over")
+
+// Store persists reserved keys and their responses.
+type Store interface {
+	// Reserve claims rec.Key for rec.Caller. If a live record already exists
+	// it is returned instead and nothing is stored. Expired records and
+	// reservations in progress for longer than LockTimeout are taken over.
+	Reserve(ctx context.Context, rec *models.IdempotencyKey) (existing *models.IdempotencyKey, err error)
+	// Complete stores the response for the reservation rec made by Reserve.
+	// If another request has taken the key over since, nothing is stored
+	// and ErrReservationLost is returned.
+	Complete(ctx context.Context, rec *models.IdempotencyKey, status int, headers json.RawMessage, body []byte) error
+	// Release drops the reservation rec so the request can be retried. It
+	// leaves the key alone if another request has taken it over.
+	Release(ctx context.Context, rec *models.IdempotencyKey) error
+}
+
+type Middleware struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func New(store Store, ttl time.Duration) *Middleware {
+	return &Middleware{store: store, ttl: ttl, now: time.Now}
+}
+
+// Handler applies to POST requests carrying an Idempotency-Key header; other
+// requests pass through. Retries with the same payload replay the stored
+// response with Idempotent-Replayed: true, a different payload gets 422 and a
+// retry while the first request is still running gets 409. Server errors are
+// not stored, so such requests can be retried with the same key. It must run
+// after authentication.
+func (m *Middleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		if len(key) > maxKeyLength {
+			problem.Abort(c, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			problem.Abort(c, http.StatusBadRequest, "Failed to read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		caller := "ip:" + c.ClientIP()
+		if userID, ok := auth.UserID(c); ok {
+			caller = "user:" + userID
+		}
+
+		now := m.now()
+		rec := &models.IdempotencyKey{
+			Key:         key,
+			Caller:      caller,
+			RequestHash: requestHash(c.Request, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(m.ttl),
+		}
+
+		existing, err := m.store.Reserve(ctx, rec)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to reserve idempotency key", "error", err)
+			c.Header("Retry-After", "5")
+			problem.Abort(c, http.StatusServiceUnavailable, "Database is unavailable")
+			return
+		}
+		if existing != nil {
+			m.replay(c, rec, existing)
+			return
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		// The outcome must be stored even if the client has gone away,
+		// otherwise its retries would see the key in progress.
+		storeCtx := context.WithoutCancel(ctx)
+
+		defer func() {
+			if p := recover(); p != nil {
+				m.release(storeCtx, rec)
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			m.release(storeCtx, rec)
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range replayedHeaders {
+			if v := c.Writer.Header().Get(name); v != "" {
+				headers[name] = v
+			}
+		}
+		encoded, _ := json.Marshal(headers)
+
+		err = m.store.Complete(storeCtx, rec, status, encoded, writer.body.Bytes())
+		if errors.Is(err, ErrReservationLost) {
+			slog.WarnContext(ctx, "Idempotency key was taken over, response not stored", "key", key)
+		} else if err != nil {
+			slog.ErrorContext(ctx, "Failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+func (m *Middleware) replay(c *gin.Context, rec, existing *models.IdempotencyKey) {
+	switch {
+	case existing.RequestHash != rec.RequestHash:
+		problem.Abort(c, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+	case existing.StatusCode == nil:
+		c.Header("Retry-After", "1")
+		problem.Abort(c, http.StatusConflict, ErrInProgress.Error())
+	default:
+		var headers map[string]string
+		_ = json.Unmarshal(existing.ResponseHeaders, &headers)
+		for name, v := range headers {
+			c.Header(name, v)
+		}
+		c.Header(ReplayedHeader, "true")
+		c.Status(*existing.StatusCode)
+		_, _ = c.Writer.Write(existing.ResponseBody)
+		c.Abort()
+	}
+}
+
+func (m *Middleware) release(ctx context.Context, rec *models.IdempotencyKey) {
+	if err := m.store.Release(ctx, rec); err != nil {
+		slog.ErrorContext(ctx, "Failed to release idempotency key", "error", err)
+	}
+}
+
+// requestHash fingerprints the method, path and body so a key can't be
+// reused for a different request.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// captureWriter keeps a copy of the response body for storage.
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,249 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]*models.IdempotencyKey)}
+}
+
+func (s *memoryStore) Reserve(_ context.Context, rec *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[rec.Caller+"|"+rec.Key]; ok && !abandoned(existing, rec.CreatedAt) {
+		copied := *existing
+		return &copied, nil
+	}
+	copied := *rec
+	s.records[rec.Caller+"|"+rec.Key] = &copied
+	return nil, nil
+}
+
+// reservation returns the in-progress record made for rec, or nil if the key
+// has been completed or taken over since.
+func (s *memoryStore) reservation(rec *models.IdempotencyKey) *models.IdempotencyKey {
+	stored := s.records[rec.Caller+"|"+rec.Key]
+	if stored == nil || stored.StatusCode != nil || !stored.CreatedAt.Equal(rec.CreatedAt) {
+		return nil
+	}
+	return stored
+}
+
+func (s *memoryStore) Complete(ctx context.Context, rec *models.IdempotencyKey, status int, headers json.RawMessage, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.reservation(rec)
+	if stored == nil {
+		return ErrReservationLost
+	}
+	stored.StatusCode = &status
+	stored.ResponseHeaders = headers
+	stored.ResponseBody = body
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, rec *models.IdempotencyKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reservation(rec) != nil {
+		delete(s.records, rec.Caller+"|"+rec.Key)
+	}
+	return nil
+}
+
+func setupRouter(store Store, calls *int, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		auth.SetUserID(c, "user1")
+		c.Next()
+	})
+	router.Use(New(store, 0).Handler())
+	router.POST("/questions", func(c *gin.Context) {
+		*calls++
+		c.Header("Location", "/questions/1")
+		c.JSON(status, gin.H{"id": *calls})
+	})
+	return router
+}
+
+func post(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(Header, key)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ReplaysResponse(t *testing.T) {
+	calls := 0
+	router := setupRouter(newMemoryStore(), &calls, http.StatusCreated)
+
+	first := post(router, "k1", `{"text":"q"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(ReplayedHeader))
+
+	second := post(router, "k1", `{"text":"q"}`)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	assert.Equal(t, "/questions/1", second.Header().Get("Location"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// Requests without a key are never deduplicated.
+	post(router, "", `{"text":"q"}`)
+	post(router, "", `{"text":"q"}`)
+	assert.Equal(t, 3, calls)
+}
+
+func TestMiddleware_DifferentPayload(t *testing.T) {
+	calls := 0
+	router := setupRouter(newMemoryStore(), &calls, http.StatusCreated)
+
+	post(router, "k1", `{"text":"q"}`)
+	w := post(router, "k1", `{"text":"other"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	store := newMemoryStore()
+	calls := 0
+	router := setupRouter(store, &calls, http.StatusCreated)
+
+	_, _ = store.Reserve(context.Background(), &models.IdempotencyKey{
+		Key:         "k1",
+		Caller:      "user:user1",
+		CreatedAt:   time.Now(),
+		RequestHash: requestHash(httptest.NewRequest("POST", "/questions", nil), []byte(`{"text":"q"}`)),
+	})
+	w := post(router, "k1", `{"text":"q"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	store := newMemoryStore()
+	calls := 0
+	router := setupRouter(store, &calls, http.StatusInternalServerError)
+
+	post(router, "k1", `{"text":"q"}`)
+	post(router, "k1", `{"text":"q"}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.records)
+}
+
+func TestMiddleware_CompletesAfterClientDisconnects(t *testing.T) {
+	store := newMemoryStore()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(New(store, time.Hour).Handler())
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	router.POST("/questions", func(c *gin.Context) {
+		disconnect()
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "POST", "/questions", strings.NewReader(`{"text":"q"}`))
+	req.Header.Set(Header, "k1")
+	router.ServeHTTP(w, req)
+
+	retry := post(router, "k1", `{"text":"q"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(ReplayedHeader))
+}
+
+func TestMiddleware_LateCompleteAfterTakeover(t *testing.T) {
+	store := newMemoryStore()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		auth.SetUserID(c, "user1")
+		c.Next()
+	})
+	clock := time.Now()
+	m := New(store, time.Hour)
+	m.now = func() time.Time { return clock }
+	router.Use(m.Handler())
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	calls := 0
+	router.POST("/questions", func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			close(started)
+			<-finish
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": calls})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(router, "k1", `{"text":"q"}`) }()
+	<-started
+
+	// The first request is still running when its reservation is taken over.
+	clock = clock.Add(LockTimeout)
+	second := post(router, "k1", `{"text":"q"}`)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.JSONEq(t, `{"id":2}`, second.Body.String())
+
+	close(finish)
+	first := <-done
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	replay := post(router, "k1", `{"text":"q"}`)
+	assert.Equal(t, "true", replay.Header().Get(ReplayedHeader))
+	assert.Equal(t, second.Body.String(), replay.Body.String())
+	assert.Equal(t, 2, calls)
+}
+
+func TestAbandoned(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	status := http.StatusCreated
+
+	assert.False(t, abandoned(&models.IdempotencyKey{CreatedAt: now.Add(-time.Second)}, now))
+	assert.True(t, abandoned(&models.IdempotencyKey{CreatedAt: now.Add(-LockTimeout)}, now))
+	assert.False(t, abandoned(&models.IdempotencyKey{CreatedAt: now.Add(-time.Hour), StatusCode: &status}, now))
+}
+
+func TestMiddleware_KeyTooLong(t *testing.T) {
+	calls := 0
+	router := setupRouter(newMemoryStore(), &calls, http.StatusCreated)
+
+	w := post(router, strings.Repeat("k", maxKeyLength+1), `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, calls)
+}
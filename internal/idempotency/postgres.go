@@ -0,0 +1,123 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const sweepInterval = 10 * time.Minute
+
+// PostgresStore keeps keys in the idempotency_keys table. Expired keys are
+// ignored on lookup and deleted in the background of regular calls.
+type PostgresStore struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Reserve(ctx context.Context, rec *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	// created_at identifies the reservation in Complete and Release, so it
+	// must compare equal after a round trip through Postgres, which keeps
+	// microseconds.
+	rec.CreatedAt = rec.CreatedAt.Truncate(time.Microsecond)
+
+	s.sweep(ctx, rec.CreatedAt)
+
+	db := s.db.WithContext(ctx)
+
+	// A second attempt is needed only when a stale record had to be
+	// replaced.
+	for attempt := 0; attempt < 2; attempt++ {
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil, nil
+		}
+
+		var existing models.IdempotencyKey
+		err := db.Where("key = ? AND caller = ?", rec.Key, rec.Caller).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		expired := !existing.ExpiresAt.After(rec.CreatedAt)
+		if !expired && !abandoned(&existing, rec.CreatedAt) {
+			return &existing, nil
+		}
+
+		// The condition on expires_at identifies this very reservation; an
+		// abandoned one is only dropped if it still hasn't completed.
+		del := db.Where("key = ? AND caller = ? AND expires_at = ?", rec.Key, rec.Caller, existing.ExpiresAt)
+		if !expired {
+			del = del.Where("status_code IS NULL")
+		}
+		if err := del.Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, ErrInProgress
+}
+
+// abandoned reports whether rec is a reservation still in progress after
+// LockTimeout.
+func abandoned(rec *models.IdempotencyKey, now time.Time) bool {
+	return rec.StatusCode == nil && now.Sub(rec.CreatedAt) >= LockTimeout
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, rec *models.IdempotencyKey, status int, headers json.RawMessage, body []byte) error {
+	result := s.reservation(ctx, rec).
+		Model(&models.IdempotencyKey{}).
+		Updates(map[string]interface{}{
+			"status_code":      status,
+			"response_headers": headers,
+			"response_body":    body,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+func (s *PostgresStore) Release(ctx context.Context, rec *models.IdempotencyKey) error {
+	return s.reservation(ctx, rec).Delete(&models.IdempotencyKey{}).Error
+}
+
+// reservation scopes a query to the in-progress row rec created. A row that
+// was taken over after LockTimeout has a later created_at and is left alone.
+func (s *PostgresStore) reservation(ctx context.Context, rec *models.IdempotencyKey) *gorm.DB {
+	return s.db.WithContext(ctx).
+		Where("key = ? AND caller = ? AND created_at = ? AND status_code IS NULL", rec.Key, rec.Caller, rec.CreatedAt)
+}
+
+// sweep deletes expired keys at most once per sweepInterval per instance.
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+}
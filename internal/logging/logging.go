@@ -0,0 +1,42 @@
+// Package logging adds request-scoped attributes to slog records.
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextHandler wraps a slog.Handler and adds the request, trace and span
+// IDs found in the record's context. Use the *Context logging functions for
+// the IDs to be available.
+type ContextHandler struct {
+	slog.Handler
+}
+
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
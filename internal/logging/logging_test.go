@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestContextHandler_AddsIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	ctx = requestid.WithID(ctx, "req-42")
+
+	logger.InfoContext(ctx, "with span")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+	assert.Equal(t, "req-42", record["request_id"])
+	assert.Equal(t, "test", record["component"])
+
+	buf.Reset()
+	logger.InfoContext(context.Background(), "without span")
+
+	record = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotContains(t, record, "trace_id")
+	assert.NotContains(t, record, "request_id")
+}
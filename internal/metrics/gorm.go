@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// GormPlugin observes the duration of every GORM operation in
+// qa_db_query_duration_seconds. Install it with db.Use(metrics.GormPlugin{}).
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, p := range processors {
+		if err := p.before("metrics:before_"+p.operation, startTimer); err != nil {
+			return err
+		}
+		if err := p.after("metrics:after_"+p.operation, observe(p.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,111 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic, database
+// queries, the connection pool and business events.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Path      = "/metrics"
+	namespace = "qa"
+
+	// unmatchedRoute labels requests that matched no route, so scanners
+	// can't blow up label cardinality with random paths.
+	unmatchedRoute = "unmatched"
+)
+
+// Registry holds every metric of the service. A dedicated registry keeps
+// metrics of imported libraries out unless added explicitly.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM query latency by operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+
+	QuestionsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "questions_created_total",
+		Help:      "Questions created.",
+	})
+
+	AnswersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "answers_created_total",
+		Help:      "Answers created.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		dbQueryDuration,
+		QuestionsCreated,
+		AnswersCreated,
+	)
+}
+
+// RegisterDBStats exports the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware records the count and latency of every request, labelled with
+// the route template (/questions/:id) rather than the raw path.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := c.Writer.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		labels := prometheus.Labels{
+			"method": c.Request.Method,
+			"route":  route,
+			"status": strconv.Itoa(status),
+		}
+
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	}
+}
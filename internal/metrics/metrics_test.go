@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/questions/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET(Path, Handler())
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_LabelsRouteTemplate(t *testing.T) {
+	router := setupRouter()
+	ok := httpRequests.WithLabelValues("GET", "/questions/:id", "200")
+	unmatched := httpRequests.WithLabelValues("GET", unmatchedRoute, "404")
+	before, beforeUnmatched := testutil.ToFloat64(ok), testutil.ToFloat64(unmatched)
+
+	get(router, "/questions/1")
+	get(router, "/questions/2")
+	get(router, "/no/such/route")
+
+	assert.Equal(t, before+2, testutil.ToFloat64(ok))
+	assert.Equal(t, beforeUnmatched+1, testutil.ToFloat64(unmatched))
+}
+
+func TestHandler_Exposition(t *testing.T) {
+	router := setupRouter()
+	QuestionsCreated.Inc()
+	get(router, "/questions/1")
+
+	w := get(router, Path)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	for _, name := range []string{
+		"qa_http_requests_total",
+		"qa_http_request_duration_seconds_bucket",
+		"qa_questions_created_total",
+		"qa_answers_created_total",
+		"go_goroutines",
+	} {
+		assert.True(t, strings.Contains(body, name), "missing %s", name)
+	}
+	assert.False(t, strings.Contains(body, `route="/questions/1"`))
+}
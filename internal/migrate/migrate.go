@@ -0,0 +1,124 @@
+// Package migrate applies the embedded goose migrations. Every run holds a
+// Postgres advisory lock, so instances starting together apply each
+// migration once.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NKV510/question-answer-api/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// ErrNoMigrations is returned by Down and Redo when nothing is applied.
+var ErrNoMigrations = errors.New("no migrations to roll back")
+
+type Runner struct {
+	provider *goose.Provider
+}
+
+// New returns a runner for the migrations embedded in the binary.
+func New(db *sql.DB) (*Runner, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{provider: provider}, nil
+}
+
+// Up applies all pending migrations.
+func (r *Runner) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return r.provider.Up(ctx)
+}
+
+// Down rolls back the most recent migration.
+func (r *Runner) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	res, err := r.provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return nil, ErrNoMigrations
+	}
+	return res, err
+}
+
+// Redo rolls back the most recent migration and applies it again.
+func (r *Runner) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := r.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+	up, err := r.provider.ApplyVersion(ctx, down.Source.Version, true)
+	if err != nil {
+		return []*goose.MigrationResult{down}, err
+	}
+	return []*goose.MigrationResult{down, up}, nil
+}
+
+func (r *Runner) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return r.provider.Status(ctx)
+}
+
+// Sources lists the embedded migrations in version order.
+func (r *Runner) Sources() []*goose.Source {
+	return r.provider.ListSources()
+}
+
+func (r *Runner) Version(ctx context.Context) (int64, error) {
+	return r.provider.GetDBVersion(ctx)
+}
+
+func (r *Runner) Close() error {
+	return r.provider.Close()
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+const template = `-- +goose Up
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+`
+
+// Create writes an empty migration to dir, named like the existing ones:
+// <timestamp>_<sequence>_<name>.sql. It returns the path of the new file.
+func Create(dir, name string, now time.Time) (string, error) {
+	slug := strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" {
+		return "", fmt.Errorf("invalid migration name %q", name)
+	}
+
+	existing, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return "", err
+	}
+
+	file := fmt.Sprintf("%s_%03d_%s.sql", now.UTC().Format("20060102150405"), len(existing)+1, slug)
+	path := filepath.Join(dir, file)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(template); err != nil {
+		f.Close()
+		return "", err
+	}
+	return path, f.Close()
+}
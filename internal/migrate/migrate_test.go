@@ -0,0 +1,57 @@
+package migrate
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNew_ParsesEmbeddedMigrations needs no database: sql.Open doesn't
+// connect, and the provider parses the embedded files up front.
+func TestNew_ParsesEmbeddedMigrations(t *testing.T) {
+	db, err := sql.Open("pgx", "postgres://localhost/unused")
+	require.NoError(t, err)
+	defer db.Close()
+
+	runner, err := New(db)
+	require.NoError(t, err)
+	defer runner.Close()
+
+	sources := runner.Sources()
+	require.NotEmpty(t, sources)
+	for i, src := range sources {
+		assert.True(t, strings.HasSuffix(src.Path, ".sql"), src.Path)
+		if i > 0 {
+			assert.Greater(t, src.Version, sources[i-1].Version)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20251125203627_001_create_questions_table.sql"), nil, 0o644))
+	now := time.Date(2026, 1, 7, 9, 30, 0, 0, time.UTC)
+
+	path, err := Create(dir, "Add user bio!", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "20260107093000_002_add_user_bio.sql"), path)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "-- +goose Up")
+	assert.Contains(t, string(content), "-- +goose Down")
+
+	path, err = Create(dir, "drop_user_bio", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "20260107093100_003_drop_user_bio.sql"), path)
+
+	_, err = Create(dir, "!!!", now)
+	assert.Error(t, err)
+}
@@ -1,18 +1,195 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Role is ordered: every role has the permissions of the ones below it.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var roleRank = map[Role]int{RoleUser: 1, RoleModerator: 2, RoleAdmin: 3}
+
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// AtLeast reports whether r grants the permissions of min. Unknown roles
+// grant nothing.
+func (r Role) AtLeast(min Role) bool {
+	return roleRank[r] > 0 && roleRank[r] >= roleRank[min]
+}
+
+type User struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	Handle      string    `json:"handle" gorm:"not null;uniqueIndex"`
+	DisplayName string    `json:"display_name" gorm:"not null"`
+	Role        Role      `json:"role" gorm:"size:16;not null;default:user"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type QuestionStatus string
+
+const (
+	QuestionOpen      QuestionStatus = "open"
+	QuestionClosed    QuestionStatus = "closed"
+	QuestionLocked    QuestionStatus = "locked"
+	QuestionDuplicate QuestionStatus = "duplicate"
+)
 
 type Question struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Text      string    `json:"text" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
-	Answers   []Answer  `json:"answers,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	AuthorID         *string        `json:"author_id" gorm:"index"`
+	Text             string         `json:"text" gorm:"not null"`
+	AcceptedAnswerID *uint          `json:"accepted_answer_id" gorm:"index"`
+	Status           QuestionStatus `json:"status" gorm:"size:16;not null;default:open;index"`
+	CloseReason      *string        `json:"close_reason,omitempty"`
+	DuplicateOf      *uint          `json:"duplicate_of,omitempty"`
+	Version          uint           `json:"version" gorm:"not null;default:1"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"index"`
+	DeletedAt        gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	AnswerCount      int64          `json:"answer_count" gorm:"->;-:migration"`
+	Tags             []Tag          `json:"tags" gorm:"many2many:question_tags;constraint:OnDelete:CASCADE"`
+	Answers          []Answer       `json:"answers,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+	Comments         []Comment      `json:"comments,omitempty" gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE"`
+}
+
+// Locked reports whether the question and its answers are frozen: no edits,
+// comments or votes.
+func (q *Question) Locked() bool {
+	return q.Status == QuestionLocked
+}
+
+// AcceptsAnswers reports whether new answers may be posted. Only open
+// questions take answers.
+func (q *Question) AcceptsAnswers() bool {
+	return q.Status == "" || q.Status == QuestionOpen
+}
+
+type QuestionStatusChange struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	QuestionID  uint           `json:"question_id" gorm:"not null;index"`
+	FromStatus  QuestionStatus `json:"from_status" gorm:"size:16;not null"`
+	ToStatus    QuestionStatus `json:"to_status" gorm:"size:16;not null"`
+	Reason      *string        `json:"reason,omitempty"`
+	DuplicateOf *uint          `json:"duplicate_of,omitempty"`
+	ChangedBy   string         `json:"changed_by" gorm:"not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type Tag struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"size:32;not null;uniqueIndex"`
+	CreatedAt  time.Time `json:"created_at"`
+	UsageCount int64     `json:"usage_count" gorm:"->;-:migration"`
 }
 
 type Answer struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	QuestionID uint           `json:"question_id" gorm:"not null;index"`
+	UserID     string         `json:"user_id" gorm:"not null;index"`
+	Text       string         `json:"text" gorm:"not null"`
+	Score      int            `json:"score" gorm:"not null;default:0"`
+	Version    uint           `json:"version" gorm:"not null;default:1"`
+	CreatedAt  time.Time      `json:"created_at"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	Accepted   bool           `json:"accepted,omitempty" gorm:"-"`
+	Comments   []Comment      `json:"comments,omitempty" gorm:"foreignKey:AnswerID;constraint:OnDelete:CASCADE"`
+}
+
+// Comment belongs to exactly one of a question or an answer.
+type Comment struct {
 	ID         uint      `json:"id" gorm:"primaryKey"`
-	QuestionID uint      `json:"question_id" gorm:"not null;index"`
+	QuestionID *uint     `json:"question_id,omitempty" gorm:"index"`
+	AnswerID   *uint     `json:"answer_id,omitempty" gorm:"index"`
 	UserID     string    `json:"user_id" gorm:"not null;index"`
 	Text       string    `json:"text" gorm:"not null"`
 	CreatedAt  time.Time `json:"created_at"`
 }
+
+type AnswerVote struct {
+	AnswerID  uint      `json:"answer_id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"primaryKey;index"`
+	Value     int       `json:"value" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type QuestionRevision struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	QuestionID   uint      `json:"question_id" gorm:"not null;index"`
+	Editor       string    `json:"editor" gorm:"not null"`
+	PreviousText string    `json:"previous_text" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type AnswerRevision struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AnswerID     uint      `json:"answer_id" gorm:"not null;index"`
+	Editor       string    `json:"editor" gorm:"not null"`
+	PreviousText string    `json:"previous_text" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuditEvent is an append-only record of a change. Before and After are JSON
+// snapshots of the entity; Before is null for creates and After for deletes.
+type AuditEvent struct {
+	ID         uint64          `json:"id" gorm:"primaryKey"`
+	Actor      *string         `json:"actor" gorm:"index"`
+	Action     string          `json:"action" gorm:"size:64;not null"`
+	EntityType string          `json:"entity_type" gorm:"size:32;not null"`
+	EntityID   string          `json:"entity_id" gorm:"not null"`
+	Before     json.RawMessage `json:"before,omitempty" gorm:"type:jsonb"`
+	After      json.RawMessage `json:"after,omitempty" gorm:"type:jsonb"`
+	RequestID  *string         `json:"request_id,omitempty"`
+	ClientIP   *string         `json:"client_ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"index"`
+}
+
+// RateLimitBucket is the shared token bucket state used by the Postgres rate
+// limit store.
+type RateLimitBucket struct {
+	Key       string    `gorm:"primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index"`
+}
+
+// IdempotencyKey stores the first response to a POST sent with an
+// Idempotency-Key header. StatusCode is nil while the request is in flight.
+type IdempotencyKey struct {
+	Key             string `gorm:"primaryKey;size:255"`
+	Caller          string `gorm:"primaryKey;size:255"`
+	RequestHash     string `gorm:"size:64;not null"`
+	StatusCode      *int
+	ResponseHeaders json.RawMessage `gorm:"type:jsonb"`
+	ResponseBody    []byte
+	CreatedAt       time.Time
+	ExpiresAt       time.Time `gorm:"not null;index"`
+}
+
+// All lists the models backed by a table in the migrations. Join tables of
+// many2many fields are implied by their owners.
+func All() []any {
+	return []any{
+		&User{},
+		&Question{},
+		&QuestionStatusChange{},
+		&Tag{},
+		&Answer{},
+		&Comment{},
+		&AnswerVote{},
+		&QuestionRevision{},
+		&AnswerRevision{},
+		&AuditEvent{},
+		&RateLimitBucket{},
+		&IdempotencyKey{},
+	}
+}
@@ -0,0 +1,96 @@
+// Package openapi builds the OpenAPI 3.1 description of the HTTP API and
+// serves it together with a documentation page.
+package openapi
+
+// Document is the subset of the OpenAPI 3.1 object model used by this API.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	Parameters      map[string]*Parameter      `json:"parameters"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema is a JSON Schema (draft 2020-12) as used by OpenAPI 3.1. Type is a
+// string or, for nullable values, a list of strings.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
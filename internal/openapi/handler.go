@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+// docsPage renders the spec with Redoc, loaded from its CDN.
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Question-Answer API</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="` + SpecPath + `"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+)
+
+// SpecHandler serves the OpenAPI document. It is built once on first use.
+func SpecHandler(c *gin.Context) {
+	specOnce.Do(func() {
+		specJSON, _ = json.MarshalIndent(Build(), "", "  ")
+	})
+	c.Data(http.StatusOK, "application/json; charset=utf-8", specJSON)
+}
+
+// DocsHandler serves the interactive documentation page.
+func DocsHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
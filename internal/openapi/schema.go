@@ -0,0 +1,217 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+)
+
+// generator derives component schemas from Go types through their json and
+// binding tags, so the spec can't drift from the structs handlers actually
+// encode and bind.
+type generator struct {
+	schemas map[string]*Schema
+	enums   map[reflect.Type][]interface{}
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: make(map[string]*Schema),
+		enums:   make(map[reflect.Type][]interface{}),
+	}
+}
+
+// enum documents the values of a named string type such as
+// models.QuestionStatus.
+func (g *generator) enum(v interface{}, values ...interface{}) {
+	g.enums[reflect.TypeOf(v)] = values
+}
+
+// request returns a reference to the schema of a request body struct.
+// Required fields come from binding:"required".
+func (g *generator) request(v interface{}) *Schema {
+	return g.structRef(reflect.TypeOf(v), true)
+}
+
+// response returns the schema of a response value. Fields without omitempty
+// are always encoded, so they are marked required.
+func (g *generator) response(v interface{}) *Schema {
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	if values, ok := g.enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case deletedAtType:
+		return &Schema{Type: []string{"string", "null"}, Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.typeSchema(t.Elem())
+		if typ, ok := s.Type.(string); ok {
+			s.Type = []string{typ, "null"}
+		}
+		return s
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t, false)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) structRef(t reflect.Type, request bool) *Schema {
+	name := schemaName(t)
+	if _, ok := g.schemas[name]; !ok {
+		// Register before walking the fields so recursive types terminate.
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		g.schemas[name] = s
+		g.addFields(s, t, request)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type, request bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(s, field.Type, request)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.typeSchema(field.Type)
+		required := applyBinding(prop, field.Tag.Get("binding"))
+		if !request {
+			required = !strings.Contains(opts, "omitempty")
+		}
+
+		s.Properties[name] = prop
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyBinding copies validator rules onto the schema and reports whether
+// the field is required.
+func applyBinding(s *Schema, binding string) (required bool) {
+	if binding == "" {
+		return false
+	}
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			setBound(s, key == "min", n)
+		case "oneof":
+			if s.Enum != nil {
+				continue
+			}
+			for _, v := range strings.Fields(value) {
+				if s.Type == "integer" {
+					n, _ := strconv.Atoi(v)
+					s.Enum = append(s.Enum, n)
+				} else {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+		}
+	}
+	return required
+}
+
+func setBound(s *Schema, lower bool, n int) {
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		if lower {
+			s.Minimum = float(n)
+		} else {
+			s.Maximum = float(n)
+		}
+	}
+}
+
+// schemaName uses the Go type name. Types outside the API packages get their
+// package as a prefix, so diff.Op becomes DiffOp.
+func schemaName(t reflect.Type) string {
+	switch pkg := path.Base(t.PkgPath()); pkg {
+	case "models", "handlers", "repository", "problem":
+		return t.Name()
+	default:
+		r := []rune(pkg)
+		r[0] = unicode.ToUpper(r[0])
+		return string(r) + t.Name()
+	}
+}
+
+func float(n int) *float64 {
+	f := float64(n)
+	return &f
+}
@@ -0,0 +1,438 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NKV510/question-answer-api/internal/diff"
+	"github.com/NKV510/question-answer-api/internal/handlers"
+	"github.com/NKV510/question-answer-api/internal/health"
+	"github.com/NKV510/question-answer-api/internal/metrics"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/NKV510/question-answer-api/internal/repository"
+)
+
+// route describes one operation. Paths use gin syntax (":id") so they can be
+// compared with the router; path parameters are integers unless listed in
+// stringParams.
+type route struct {
+	method       string
+	path         string
+	summary      string
+	tag          string
+	auth         bool
+	role         models.Role
+	stringParams []string
+	query        []*Parameter
+	ifMatch      bool
+	ifNoneMatch  bool
+	body         interface{}
+	status       int
+	response     interface{}
+	etag         bool
+	errors       []int
+}
+
+var errorResponses = map[int]struct{ name, description string }{
+	http.StatusBadRequest:          {"BadRequest", "Invalid path, query or body"},
+	http.StatusUnauthorized:        {"Unauthorized", "Missing or invalid bearer token"},
+	http.StatusForbidden:           {"Forbidden", "Caller lacks the required role or ownership"},
+	http.StatusNotFound:            {"NotFound", "Resource does not exist"},
+	http.StatusConflict:            {"Conflict", "Request conflicts with the current state, or an idempotent request is still in progress"},
+	http.StatusPreconditionFailed:  {"PreconditionFailed", "If-Match does not match the current version"},
+	http.StatusUnprocessableEntity: {"UnprocessableEntity", "Request is well-formed but can't be applied"},
+	http.StatusTooManyRequests:     {"TooManyRequests", "Rate limit exceeded"},
+	http.StatusInternalServerError: {"InternalError", "Unexpected server error"},
+	http.StatusServiceUnavailable:  {"ServiceUnavailable", "Database is unavailable"},
+}
+
+func query(name, typ, description string) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: &Schema{Type: typ}}
+}
+
+func enumQuery(name, description string, values ...interface{}) *Parameter {
+	p := query(name, "string", description)
+	p.Schema.Enum = values
+	return p
+}
+
+func timeQuery(name, description string) *Parameter {
+	p := query(name, "string", description)
+	p.Schema.Format = "date-time"
+	return p
+}
+
+var pageParams = []*Parameter{
+	{Ref: "#/components/parameters/Limit"},
+	{Ref: "#/components/parameters/Offset"},
+}
+
+var questionListParams = append([]*Parameter{
+	query("cursor", "string", "Opaque cursor from next_cursor; replaces offset"),
+	timeQuery("created_after", "Only questions created after this time"),
+	timeQuery("created_before", "Only questions created before this time"),
+	query("answered", "boolean", "Only questions with (true) or without (false) an accepted answer"),
+	{
+		Name:        "tag",
+		In:          "query",
+		Description: "Tag filter; repeat for several tags",
+		Schema:      &Schema{Type: "array", Items: &Schema{Type: "string"}},
+	},
+	enumQuery("tag_mode", "Whether questions need all or any of the tags (default all)", "all", "any"),
+	enumQuery("sort", "Sort key (default created_at)", "created_at", "answers"),
+	enumQuery("order", "Sort order (default desc)", "asc", "desc"),
+}, pageParams...)
+
+var routes = []route{
+	{method: "GET", path: "/questions/", summary: "List questions", tag: "questions",
+		query: questionListParams, status: 200, response: repository.QuestionPage{}},
+	{method: "POST", path: "/questions/", summary: "Create a question", tag: "questions", auth: true,
+		body: handlers.CreateQuestionRequest{}, status: 201, response: models.Question{}, etag: true},
+	{method: "GET", path: "/questions/:id", summary: "Get a question with its answers", tag: "questions",
+		query: []*Parameter{
+			enumQuery("sort", "Answer order (default oldest)", "score", "newest", "oldest"),
+			enumQuery("include", "Also return comments", "comments"),
+		},
+		ifNoneMatch: true, status: 200, response: models.Question{}, etag: true},
+	{method: "PATCH", path: "/questions/:id", summary: "Edit a question", tag: "questions", auth: true,
+		ifMatch: true, body: handlers.UpdateQuestionRequest{}, status: 200, response: models.Question{}, etag: true,
+		errors: []int{403}},
+	{method: "DELETE", path: "/questions/:id", summary: "Move a question and its answers to the trash", tag: "questions", auth: true,
+		ifMatch: true, status: 204, errors: []int{403}},
+	{method: "POST", path: "/questions/:id/restore", summary: "Restore a question from the trash", tag: "trash", auth: true,
+		ifMatch: true, status: 200, response: models.Question{}, etag: true, errors: []int{403}},
+	{method: "GET", path: "/questions/:id/revisions", summary: "List question revisions", tag: "revisions",
+		status: 200, response: []models.QuestionRevision{}},
+	{method: "GET", path: "/questions/:id/revisions/diff", summary: "Diff two question revisions", tag: "revisions",
+		query: []*Parameter{
+			{Name: "from", In: "query", Required: true, Description: "Revision ID or current", Schema: &Schema{Type: "string"}},
+			query("to", "string", "Revision ID or current (default current)"),
+		},
+		status: 200, response: handlers.RevisionDiffResponse{}, errors: []int{422}},
+	{method: "POST", path: "/questions/:id/accept/:answer_id", summary: "Accept an answer", tag: "questions", auth: true,
+		ifMatch: true, status: 200, response: models.Question{}, etag: true, errors: []int{403}},
+	{method: "DELETE", path: "/questions/:id/accept/:answer_id", summary: "Unaccept an answer", tag: "questions", auth: true,
+		ifMatch: true, status: 200, response: models.Question{}, etag: true, errors: []int{403}},
+	{method: "POST", path: "/questions/:id/status", summary: "Change question status", tag: "questions", auth: true,
+		role: models.RoleModerator, ifMatch: true, body: handlers.ChangeStatusRequest{},
+		status: 200, response: models.Question{}, etag: true, errors: []int{422}},
+	{method: "GET", path: "/questions/:id/status/history", summary: "List status changes", tag: "questions",
+		status: 200, response: []models.QuestionStatusChange{}},
+	{method: "GET", path: "/questions/:id/comments", summary: "List question comments", tag: "comments",
+		status: 200, response: []models.Comment{}},
+	{method: "POST", path: "/questions/:id/comments", summary: "Comment on a question", tag: "comments", auth: true,
+		ifMatch: true, body: handlers.CreateCommentRequest{}, status: 201, response: models.Comment{}},
+	{method: "POST", path: "/questions/:id/answers", summary: "Answer a question", tag: "answers", auth: true,
+		ifMatch: true, body: handlers.CreateAnswerRequest{}, status: 201, response: models.Answer{}, etag: true},
+
+	{method: "GET", path: "/answers/:id", summary: "Get an answer", tag: "answers",
+		ifNoneMatch: true, status: 200, response: models.Answer{}, etag: true},
+	{method: "PATCH", path: "/answers/:id", summary: "Edit an answer", tag: "answers", auth: true,
+		ifMatch: true, body: handlers.UpdateAnswerRequest{}, status: 200, response: models.Answer{}, etag: true,
+		errors: []int{403}},
+	{method: "DELETE", path: "/answers/:id", summary: "Delete an answer", tag: "answers", auth: true,
+		ifMatch: true, status: 204, errors: []int{403}},
+	{method: "GET", path: "/answers/:id/revisions", summary: "List answer revisions", tag: "revisions",
+		status: 200, response: []models.AnswerRevision{}},
+	{method: "GET", path: "/answers/:id/revisions/diff", summary: "Diff two answer revisions", tag: "revisions",
+		query: []*Parameter{
+			{Name: "from", In: "query", Required: true, Description: "Revision ID or current", Schema: &Schema{Type: "string"}},
+			query("to", "string", "Revision ID or current (default current)"),
+		},
+		status: 200, response: handlers.RevisionDiffResponse{}, errors: []int{422}},
+	{method: "POST", path: "/answers/:id/vote", summary: "Vote for an answer", tag: "answers", auth: true,
+		ifMatch: true, body: handlers.VoteRequest{}, status: 200, response: models.Answer{}, etag: true},
+	{method: "DELETE", path: "/answers/:id/vote", summary: "Retract a vote", tag: "answers", auth: true,
+		ifMatch: true, status: 200, response: models.Answer{}, etag: true},
+	{method: "GET", path: "/answers/:id/comments", summary: "List answer comments", tag: "comments",
+		status: 200, response: []models.Comment{}},
+	{method: "POST", path: "/answers/:id/comments", summary: "Comment on an answer", tag: "comments", auth: true,
+		ifMatch: true, body: handlers.CreateCommentRequest{}, status: 201, response: models.Comment{}},
+	{method: "DELETE", path: "/comments/:id", summary: "Delete a comment", tag: "comments", auth: true,
+		status: 204, errors: []int{403}},
+
+	{method: "GET", path: "/users/:id", summary: "Get a user profile", tag: "users",
+		stringParams: []string{"id"}, status: 200, response: models.User{}},
+	{method: "GET", path: "/users/:id/answers", summary: "List answers by a user", tag: "users",
+		stringParams: []string{"id"}, query: pageParams, status: 200, response: repository.AnswerPage{}},
+	{method: "GET", path: "/users/:id/questions", summary: "List questions by a user", tag: "users",
+		stringParams: []string{"id"}, query: questionListParams, status: 200, response: repository.QuestionPage{}},
+
+	{method: "GET", path: "/search", summary: "Full-text search", tag: "search",
+		query: append([]*Parameter{
+			{Name: "q", In: "query", Required: true, Description: "Search query", Schema: &Schema{Type: "string"}},
+			enumQuery("type", "Restrict to questions or answers", "question", "answer"),
+		}, pageParams...),
+		status: 200, response: repository.SearchResult{}},
+	{method: "GET", path: "/tags", summary: "List tags by usage", tag: "tags",
+		query: pageParams, status: 200, response: repository.TagPage{}},
+	{method: "GET", path: "/trash", summary: "List deleted questions", tag: "trash", auth: true,
+		query: pageParams, status: 200, response: repository.QuestionPage{}},
+
+	{method: "PUT", path: "/admin/users/:id/role", summary: "Set a user's role", tag: "admin", auth: true,
+		role: models.RoleAdmin, stringParams: []string{"id"}, body: handlers.SetRoleRequest{},
+		status: 200, response: models.User{}},
+	{method: "DELETE", path: "/admin/users/:id/role", summary: "Reset a user's role to user", tag: "admin", auth: true,
+		role: models.RoleAdmin, stringParams: []string{"id"}, status: 200, response: models.User{}},
+	{method: "GET", path: "/admin/audit", summary: "List audit events", tag: "admin", auth: true,
+		role: models.RoleAdmin,
+		query: append([]*Parameter{
+			query("actor", "string", "User ID of the actor"),
+			query("action", "string", "Action, for example question.update"),
+			query("entity_type", "string", "Entity type, for example question"),
+			query("entity_id", "string", "Entity ID"),
+			timeQuery("from", "Only events at or after this time"),
+			timeQuery("to", "Only events before this time"),
+		}, pageParams...),
+		status: 200, response: repository.AuditPage{}},
+}
+
+// Build assembles the document from the route table.
+func Build() *Document {
+	g := newGenerator()
+	g.enum(models.QuestionStatus(""), "open", "closed", "locked", "duplicate")
+	g.enum(models.Role(""), "user", "moderator", "admin")
+	g.enum(repository.SearchType(""), "question", "answer")
+	g.enum(diff.OpType(""), "equal", "insert", "delete")
+
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "Question-Answer API",
+			Version:     "1.0.0",
+			Description: "Questions, answers, comments and moderation. Errors are returned as application/problem+json (RFC 9457).",
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			Responses: make(map[string]*Response),
+			Parameters: map[string]*Parameter{
+				"Limit":          {Name: "limit", In: "query", Description: "Page size (1-100)", Schema: &Schema{Type: "integer", Minimum: float(1), Maximum: float(repository.MaxPageLimit)}},
+				"Offset":         {Name: "offset", In: "query", Description: "Number of items to skip", Schema: &Schema{Type: "integer", Minimum: float(0)}},
+				"IfMatch":        {Name: "If-Match", In: "header", Description: "ETag of the version being modified", Schema: &Schema{Type: "string"}},
+				"IfNoneMatch":    {Name: "If-None-Match", In: "header", Description: "ETag held by the client; 304 is returned if it is current", Schema: &Schema{Type: "string"}},
+				"IdempotencyKey": {Name: "Idempotency-Key", In: "header", Description: "Makes the request safe to retry; the first response is replayed", Schema: &Schema{Type: "string", MaxLength: intPtr(255)}},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	errorSchema := g.response(problem.Problem{})
+	for status, r := range errorResponses {
+		headers := map[string]*Header(nil)
+		if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			headers = map[string]*Header{"Retry-After": {Description: "Seconds to wait before retrying", Schema: &Schema{Type: "integer"}}}
+		}
+		doc.Components.Responses[r.name] = &Response{
+			Description: r.description,
+			Headers:     headers,
+			Content:     map[string]*MediaType{problem.ContentType: {Schema: errorSchema}},
+		}
+	}
+
+	for _, r := range routes {
+		doc.addRoute(g, r)
+	}
+	doc.addDocsRoutes()
+	doc.addHealthRoutes(g)
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+func (d *Document) addRoute(g *generator, r route) {
+	path, params := convertPath(r.path, r.stringParams)
+
+	op := &Operation{
+		OperationID: operationID(r.method, r.path),
+		Summary:     r.summary,
+		Tags:        []string{r.tag},
+		Parameters:  append(params, r.query...),
+		Responses:   make(map[string]*Response),
+	}
+
+	if r.ifMatch {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/IfMatch"})
+	}
+	if r.ifNoneMatch {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/IfNoneMatch"})
+	}
+	if r.method == http.MethodPost {
+		op.Parameters = append(op.Parameters, &Parameter{Ref: "#/components/parameters/IdempotencyKey"})
+	}
+	if r.body != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.request(r.body))}
+	}
+
+	success := &Response{Description: http.StatusText(r.status)}
+	if r.response != nil {
+		success.Content = jsonContent(g.response(r.response))
+	}
+	if r.etag {
+		success.Headers = map[string]*Header{"ETag": {Description: "Current version of the resource", Schema: &Schema{Type: "string"}}}
+	}
+	op.Responses[strconv.Itoa(r.status)] = success
+	if r.ifNoneMatch {
+		op.Responses["304"] = &Response{Description: "Not modified"}
+	}
+
+	errs := append([]int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable}, r.errors...)
+	if len(params) > 0 || len(r.query) > 0 || r.body != nil {
+		errs = append(errs, http.StatusBadRequest)
+	}
+	if len(params) > 0 {
+		errs = append(errs, http.StatusNotFound)
+	}
+	if r.auth {
+		op.Security = []map[string][]string{{"bearerAuth": {}}}
+		errs = append(errs, http.StatusUnauthorized)
+	}
+	if r.role != "" {
+		errs = append(errs, http.StatusForbidden)
+	}
+	if r.method != http.MethodGet {
+		errs = append(errs, http.StatusConflict)
+	}
+	if r.ifMatch {
+		errs = append(errs, http.StatusPreconditionFailed)
+	}
+	if r.method == http.MethodPost {
+		errs = append(errs, http.StatusUnprocessableEntity)
+	}
+	for _, status := range errs {
+		op.Responses[strconv.Itoa(status)] = &Response{Ref: "#/components/responses/" + errorResponses[status].name}
+	}
+
+	d.addOperation(path, r.method, op)
+}
+
+func (d *Document) addDocsRoutes() {
+	d.addOperation(SpecPath, http.MethodGet, &Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "This OpenAPI document",
+		Tags:        []string{"docs"},
+		Responses:   map[string]*Response{"200": {Description: "OK", Content: jsonContent(&Schema{Type: "object"})}},
+	})
+	d.addOperation(metrics.Path, http.MethodGet, &Operation{
+		OperationID: "getMetrics",
+		Summary:     "Prometheus metrics",
+		Tags:        []string{"docs"},
+		Responses: map[string]*Response{"200": {
+			Description: "Metrics in the Prometheus text format",
+			Content:     map[string]*MediaType{"text/plain": {Schema: &Schema{Type: "string"}}},
+		}},
+	})
+	d.addOperation(DocsPath, http.MethodGet, &Operation{
+		OperationID: "getDocs",
+		Summary:     "Interactive API documentation",
+		Tags:        []string{"docs"},
+		Responses: map[string]*Response{"200": {
+			Description: "OK",
+			Content:     map[string]*MediaType{"text/html": {Schema: &Schema{Type: "string"}}},
+		}},
+	})
+}
+
+func (d *Document) addHealthRoutes(g *generator) {
+	d.addOperation(health.LivePath, http.MethodGet, &Operation{
+		OperationID: "getLivez",
+		Summary:     "Liveness probe",
+		Tags:        []string{"health"},
+		Responses:   map[string]*Response{"200": {Description: "Process is running", Content: jsonContent(&Schema{Type: "object"})}},
+	})
+
+	report := g.response(health.Report{})
+	d.addOperation(health.ReadyPath, http.MethodGet, &Operation{
+		OperationID: "getReadyz",
+		Summary:     "Readiness probe with database check, migration version and build info",
+		Tags:        []string{"health"},
+		Responses: map[string]*Response{
+			"200": {Description: "Ready to serve traffic", Content: jsonContent(report)},
+			"503": {Description: "Database is unreachable or the instance is shutting down", Content: jsonContent(report)},
+		},
+	})
+}
+
+func (d *Document) addOperation(path, method string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// HasOperation reports whether the document describes method on a gin route
+// path such as /questions/:id.
+func (d *Document) HasOperation(method, ginPath string) bool {
+	path, _ := convertPath(ginPath, nil)
+	_, ok := d.Paths[path][strings.ToLower(method)]
+	return ok
+}
+
+// Operations lists the documented routes as "METHOD /gin/path", sorted.
+func (d *Document) Operations() []string {
+	var ops []string
+	for path, item := range d.Paths {
+		for method := range item {
+			ops = append(ops, strings.ToUpper(method)+" "+ginPath(path))
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// convertPath turns /questions/:id into /questions/{id} and returns the
+// matching path parameters.
+func convertPath(path string, stringParams []string) (string, []*Parameter) {
+	var params []*Parameter
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, ":") {
+			continue
+		}
+		name := seg[1:]
+		segments[i] = "{" + name + "}"
+
+		schema := &Schema{Type: "integer", Minimum: float(1)}
+		for _, s := range stringParams {
+			if s == name {
+				schema = &Schema{Type: "string"}
+			}
+		}
+		params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func ginPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segments[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives a stable camelCase ID such as postQuestionsIdAcceptAnswerId.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == ':' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
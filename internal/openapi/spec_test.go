@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild_RefsResolve(t *testing.T) {
+	doc := Build()
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	components := raw["components"].(map[string]interface{})
+
+	refs := regexp.MustCompile(`"\$ref":"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(data), -1)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		section, _ := components[ref[1]].(map[string]interface{})
+		assert.Contains(t, section, ref[2], "unresolved $ref %s/%s", ref[1], ref[2])
+	}
+}
+
+func TestBuild_RequestSchemas(t *testing.T) {
+	schemas := Build().Components.Schemas
+
+	create := schemas["CreateQuestionRequest"]
+	require.NotNil(t, create)
+	assert.Equal(t, []string{"text"}, create.Required)
+	assert.Equal(t, 1, *create.Properties["text"].MinLength)
+
+	comment := schemas["CreateCommentRequest"]
+	require.NotNil(t, comment)
+	assert.Equal(t, 600, *comment.Properties["text"].MaxLength)
+
+	vote := schemas["VoteRequest"]
+	require.NotNil(t, vote)
+	assert.Equal(t, []interface{}{1, -1}, vote.Properties["value"].Enum)
+
+	question := schemas["Question"]
+	require.NotNil(t, question)
+	assert.Contains(t, question.Required, "id")
+	assert.NotContains(t, question.Required, "answers")
+	assert.Equal(t, []string{"integer", "null"}, question.Properties["accepted_answer_id"].Type)
+}
+
+func TestHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(SpecPath, SpecHandler)
+	router.GET(DocsPath, DocsHandler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", SpecPath, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	assert.True(t, doc.HasOperation("POST", "/questions/:id/answers"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", DocsPath, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), SpecPath))
+}
@@ -0,0 +1,186 @@
+// Package problem renders error responses as RFC 9457 (formerly RFC 7807)
+// problem details with the application/problem+json media type.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem types. Errors that need no more than their status use
+// "about:blank", whose title is the HTTP status text.
+const (
+	TypeBlank      = "about:blank"
+	TypeValidation = "/problems/validation"
+)
+
+// Problem is a problem details object. RequestID is an extension member for
+// correlating a response with the server logs.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes one invalid field of a request body. Field is the
+// JSON name and Rule the validation tag that failed, such as "max".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
+
+func init() {
+	// Report fields by their JSON names rather than Go struct field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// New returns an about:blank problem for status.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   TypeBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Write sends p with the request path as instance and aborts the chain.
+func Write(c *gin.Context, p *Problem) {
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = requestid.FromContext(c.Request.Context())
+	}
+
+	c.Header("Content-Type", ContentType)
+	c.Abort()
+	c.Status(p.Status)
+	_ = json.NewEncoder(c.Writer).Encode(p)
+}
+
+// Abort is shorthand for Write(c, New(status, detail)).
+func Abort(c *gin.Context, status int, detail string) {
+	Write(c, New(status, detail))
+}
+
+// FromBindError turns the error of c.ShouldBindJSON into a 400 problem.
+// Validation failures are listed per field and malformed JSON is described
+// without echoing decoder internals.
+func FromBindError(err error) *Problem {
+	p := New(http.StatusBadRequest, "Invalid request body")
+
+	var validationErrs validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrs):
+		p.Type = TypeValidation
+		p.Title = "Validation failed"
+		p.Detail = "One or more fields are invalid"
+		for _, fe := range validationErrs {
+			p.Errors = append(p.Errors, FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Message: message(fe),
+			})
+		}
+	case errors.As(err, &typeErr):
+		p.Type = TypeValidation
+		p.Title = "Validation failed"
+		p.Detail = "One or more fields are invalid"
+		p.Errors = []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + jsonType(typeErr.Type),
+		}}
+	case errors.As(err, &syntaxErr):
+		p.Detail = fmt.Sprintf("Malformed JSON at offset %d", syntaxErr.Offset)
+	case errors.Is(err, io.EOF):
+		p.Detail = "Request body is empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		p.Detail = "Malformed JSON: unexpected end of input"
+	}
+	return p
+}
+
+func message(fe validator.FieldError) string {
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param() + unit
+	case "max":
+		return "must be at most " + fe.Param() + unit
+	case "len":
+		return "must be exactly " + fe.Param() + unit
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	default:
+		return "failed the " + fe.Tag() + " rule"
+	}
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
@@ -0,0 +1,95 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NKV510/question-answer-api/internal/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createRequest struct {
+	Text  string `json:"text" binding:"required,max=5"`
+	Value int    `json:"value" binding:"oneof=1 -1"`
+}
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestid.Middleware())
+	router.POST("/items", func(c *gin.Context) {
+		var req createRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Write(c, FromBindError(err))
+			return
+		}
+		Abort(c, http.StatusConflict, "Item already exists")
+	})
+	return router
+}
+
+func post(router *gin.Engine, body string) (*httptest.ResponseRecorder, Problem) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/items", strings.NewReader(body))
+	req.Header.Set(requestid.Header, "req-42")
+	router.ServeHTTP(w, req)
+
+	var p Problem
+	_ = json.Unmarshal(w.Body.Bytes(), &p)
+	return w, p
+}
+
+func TestAbort(t *testing.T) {
+	w, p := post(setupRouter(), `{"text":"ok","value":1}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, Problem{
+		Type:      TypeBlank,
+		Title:     "Conflict",
+		Status:    http.StatusConflict,
+		Detail:    "Item already exists",
+		Instance:  "/items",
+		RequestID: "req-42",
+	}, p)
+}
+
+func TestFromBindError_Validation(t *testing.T) {
+	w, p := post(setupRouter(), `{"text":"too long","value":2}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, TypeValidation, p.Type)
+	require.Len(t, p.Errors, 2)
+	assert.Equal(t, FieldError{Field: "text", Rule: "max", Message: "must be at most 5 characters"}, p.Errors[0])
+	assert.Equal(t, FieldError{Field: "value", Rule: "oneof", Message: "must be one of: 1, -1"}, p.Errors[1])
+}
+
+func TestFromBindError_Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		detail string
+		errors []FieldError
+	}{
+		{"empty", ``, "Request body is empty", nil},
+		{"syntax", `{"text":}`, "Malformed JSON at offset 9", nil},
+		{"truncated", `{"text":"a"`, "Malformed JSON: unexpected end of input", nil},
+		{"wrong type", `{"text":5}`, "One or more fields are invalid", []FieldError{{Field: "text", Rule: "type", Message: "must be a string"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, p := post(setupRouter(), tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.detail, p.Detail)
+			assert.Equal(t, tt.errors, p.Errors)
+			assert.NotContains(t, w.Body.String(), "json:")
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// MemoryStore keeps buckets in process memory. Limits are per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, p Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(p.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	tokens, res := refill(b.tokens, b.last, now, p)
+	b.tokens = tokens
+	b.last = now
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// sweep drops buckets that have refilled completely, since a missing bucket
+// starts full anyway.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
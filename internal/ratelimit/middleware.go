@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Limiter applies separate read and write policies per client. Clients are
+// identified by the authenticated subject, or by IP for anonymous callers.
+type Limiter struct {
+	store Store
+	read  Policy
+	write Policy
+	now   func() time.Time
+}
+
+func NewLimiter(store Store, read, write Policy) *Limiter {
+	return &Limiter{store: store, read: read, write: write, now: time.Now}
+}
+
+// Middleware enforces the limits and reports them in the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers. Rejected requests get 429
+// with Retry-After. If the store fails the request is let through, so a
+// limiter outage never takes the API down. It must run after auth.Identify
+// and before auth.RejectInvalid, so requests with a bad token are counted
+// against the client IP before they are rejected.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		class, policy := "write", l.write
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			class, policy = "read", l.read
+		}
+		if !policy.Enabled() {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if userID, ok := auth.UserID(c); ok {
+			client = "user:" + userID
+		}
+
+		ctx := c.Request.Context()
+		res, err := l.store.Take(ctx, class+":"+client, policy, l.now())
+		if err != nil {
+			slog.ErrorContext(ctx, "Rate limit store failed", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Burst, ceilSeconds(policy.refillTime())))
+
+		if !res.Allowed {
+			retryAfter := ceilSeconds(res.RetryAfter)
+			slog.WarnContext(ctx, "Rate limit exceeded", "client", client, "class", class, "retry_after", retryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			problem.Abort(c, http.StatusTooManyRequests, "Too many requests")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so limits hold
+// across instances. Each Take is one short transaction that locks the row.
+type PostgresStore struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+	// staleAge is how long an untouched bucket is kept: the longest refill
+	// time of the policies in use, after which any bucket is full again.
+	staleAge time.Duration
+}
+
+// NewPostgresStore returns a store for buckets of the given policies, which
+// determine how long idle buckets are kept.
+func NewPostgresStore(db *gorm.DB, policies ...Policy) *PostgresStore {
+	s := &PostgresStore{db: db}
+	for _, p := range policies {
+		if p.Enabled() {
+			s.staleAge = max(s.staleAge, p.refillTime())
+		}
+	}
+	return s
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error) {
+	var res Result
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.RateLimitBucket{Key: key, Tokens: float64(p.Burst), UpdatedAt: now}).Error
+		if err != nil {
+			return err
+		}
+
+		var b models.RateLimitBucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&b).Error; err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, res = refill(b.Tokens, b.UpdatedAt, now, p)
+		return tx.Model(&b).Updates(map[string]interface{}{"tokens": tokens, "updated_at": now}).Error
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	s.sweep(ctx, now, p)
+	return res, nil
+}
+
+// sweep deletes stale buckets at most once per sweepInterval per instance. A
+// policy p that was not passed to NewPostgresStore extends staleAge, so its
+// buckets are never dropped before they have refilled.
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time, p Policy) {
+	s.mu.Lock()
+	s.staleAge = max(s.staleAge, p.refillTime())
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	staleAge := s.staleAge
+	s.mu.Unlock()
+
+	s.db.WithContext(ctx).Where("updated_at < ?", now.Add(-staleAge)).Delete(&models.RateLimitBucket{})
+}
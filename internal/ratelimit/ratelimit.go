@@ -0,0 +1,72 @@
+// Package ratelimit implements per-client token bucket rate limiting with
+// pluggable bucket stores.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy describes a token bucket: Burst tokens at most, refilled at
+// PerMinute tokens per minute. A zero PerMinute disables limiting.
+type Policy struct {
+	PerMinute int
+	Burst     int
+}
+
+func (p Policy) Enabled() bool {
+	return p.PerMinute > 0 && p.Burst > 0
+}
+
+// rate is the refill speed in tokens per second.
+func (p Policy) rate() float64 {
+	return float64(p.PerMinute) / 60
+}
+
+// refillTime is how long an empty bucket takes to fill up.
+func (p Policy) refillTime() time.Duration {
+	return secondsToDuration(float64(p.Burst) / p.rate())
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token is available. It is zero
+	// for allowed requests.
+	RetryAfter time.Duration
+}
+
+// Store keeps bucket state. Take must be atomic per key.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error)
+}
+
+// refill applies the elapsed time to a bucket holding tokens at last and
+// takes one token if available. It returns the new token count and result.
+func refill(tokens float64, last, now time.Time, p Policy) (float64, Result) {
+	elapsed := now.Sub(last).Seconds()
+	if elapsed > 0 {
+		tokens = math.Min(float64(p.Burst), tokens+elapsed*p.rate())
+	}
+
+	res := Result{Limit: p.Burst}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - tokens) / p.rate())
+	}
+
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = secondsToDuration((float64(p.Burst) - tokens) / p.rate())
+	return tokens, res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,181 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/auth"
+	"github.com/NKV510/question-answer-api/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{PerMinute: 60, Burst: 2}
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+
+	res, err := store.Take(context.Background(), "k", policy, now)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	res, _ = store.Take(context.Background(), "k", policy, now)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2*time.Second, res.Reset)
+
+	res, _ = store.Take(context.Background(), "k", policy, now)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	// One token per second is refilled.
+	res, _ = store.Take(context.Background(), "k", policy, now.Add(time.Second))
+	assert.True(t, res.Allowed)
+
+	// Other keys have their own bucket.
+	res, _ = store.Take(context.Background(), "other", policy, now)
+	assert.True(t, res.Allowed)
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{PerMinute: 60, Burst: 5}
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+
+	_, _ = store.Take(context.Background(), "idle", policy, now)
+	_, _ = store.Take(context.Background(), "busy", policy, now.Add(2*time.Minute))
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
+
+func setupRouter(limiter *Limiter, userID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if userID != "" {
+		router.Use(func(c *gin.Context) {
+			auth.SetUserID(c, userID)
+			c.Next()
+		})
+	}
+	router.Use(limiter.Middleware())
+	router.GET("/questions", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/questions", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func TestMiddleware_LimitsWrites(t *testing.T) {
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(NewMemoryStore(), Policy{PerMinute: 600, Burst: 100}, Policy{PerMinute: 6, Burst: 1})
+	limiter.now = func() time.Time { return now }
+	router := setupRouter(limiter, "user1")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/questions", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "10", w.Header().Get("RateLimit-Reset"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/questions", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Retry-After"))
+
+	// Reads use their own budget.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/questions", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "100", w.Header().Get("RateLimit-Limit"))
+}
+
+func TestMiddleware_KeysByUserOrIP(t *testing.T) {
+	store := NewMemoryStore()
+	write := Policy{PerMinute: 1, Burst: 1}
+
+	for _, userID := range []string{"user1", "user2", ""} {
+		router := setupRouter(NewLimiter(store, Policy{}, write), userID)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/questions", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code, userID)
+	}
+}
+
+func TestMiddleware_ForwardedForFromTrustedProxiesOnly(t *testing.T) {
+	write := Policy{PerMinute: 1, Burst: 1}
+	send := func(router *gin.Engine, forwardedFor string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/questions", nil)
+		req.RemoteAddr = "192.0.2.1:4000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Without trusted proxies a fresh X-Forwarded-For doesn't buy a new bucket.
+	router := setupRouter(NewLimiter(NewMemoryStore(), Policy{}, write), "")
+	require.NoError(t, router.SetTrustedProxies(nil))
+	assert.Equal(t, http.StatusCreated, send(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, send(router, "203.0.113.2"))
+
+	// Behind a trusted proxy each forwarded client has its own bucket.
+	router = setupRouter(NewLimiter(NewMemoryStore(), Policy{}, write), "")
+	require.NoError(t, router.SetTrustedProxies([]string{"192.0.2.1"}))
+	assert.Equal(t, http.StatusCreated, send(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusCreated, send(router, "203.0.113.2"))
+}
+
+func TestMiddleware_DisabledPolicy(t *testing.T) {
+	router := setupRouter(NewLimiter(NewMemoryStore(), Policy{}, Policy{}), "")
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/questions", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestMiddleware_CountsRejectedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(NewMemoryStore(), Policy{PerMinute: 60, Burst: 2}, Policy{PerMinute: 60, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	authenticator, err := auth.NewAuthenticator(&config.Config{JWTSecret: "secret"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(authenticator.Identify())
+	router.Use(limiter.Middleware())
+	router.Use(auth.RejectInvalid())
+	router.GET("/questions", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/questions", nil)
+		req.Header.Set("Authorization", "Bearer guessed")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestNewPostgresStore_StaleAge(t *testing.T) {
+	store := NewPostgresStore(nil,
+		Policy{PerMinute: 300, Burst: 60},
+		Policy{PerMinute: 30, Burst: 10},
+		Policy{},
+	)
+	assert.Equal(t, 20*time.Second, store.staleAge)
+}
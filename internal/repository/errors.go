@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotFound    = errors.New("record not found")
+	ErrConflict    = errors.New("conflict")
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// ErrVersionMismatch is returned when a conditional write finds the row at a
+// version other than the one the caller expected.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// ErrNotAccepted is returned when un-accepting an answer that is not the
+// accepted one. It matches ErrNotFound.
+var ErrNotAccepted = fmt.Errorf("%w: answer is not accepted", ErrNotFound)
+
+// ErrQuestionClosed, ErrQuestionLocked and ErrInvalidTransition are conflicts
+// with the question lifecycle; all match ErrConflict, and ErrQuestionLocked
+// also matches ErrQuestionClosed.
+var (
+	ErrQuestionClosed    = fmt.Errorf("%w: question does not accept answers", ErrConflict)
+	ErrQuestionLocked    = fmt.Errorf("%w: question is locked", ErrQuestionClosed)
+	ErrInvalidTransition = fmt.Errorf("%w: invalid status transition", ErrConflict)
+)
+
+// translateError maps GORM and Postgres errors onto the sentinel errors above
+// so handlers never depend on driver details. The original error is kept in
+// the chain for logging.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) ||
+		errors.Is(err, ErrUnavailable) || errors.Is(err, ErrVersionMismatch) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505", // unique_violation
+			pgErr.Code == "23503", // foreign_key_violation
+			pgErr.Code == "23P01", // exclusion_violation
+			pgErr.Code == "40001", // serialization_failure
+			pgErr.Code == "40P01": // deadlock_detected
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		case strings.HasPrefix(pgErr.Code, "08"), // connection_exception
+			pgErr.Code == "53300", // too_many_connections
+			pgErr.Code == "57P01", // admin_shutdown
+			pgErr.Code == "57P02", // crash_shutdown
+			pgErr.Code == "57P03": // cannot_connect_now
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connErr) || errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return err
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrNotAccepted(t *testing.T) {
+	assert.ErrorIs(t, ErrNotAccepted, ErrNotFound)
+	assert.Equal(t, ErrNotAccepted, translateError(ErrNotAccepted))
+}
+
+func TestErrQuestionLocked(t *testing.T) {
+	assert.ErrorIs(t, ErrQuestionLocked, ErrQuestionClosed)
+	assert.ErrorIs(t, ErrQuestionLocked, ErrConflict)
+	assert.Equal(t, ErrQuestionLocked, translateError(ErrQuestionLocked))
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type QuestionSort string
+
+const (
+	SortByCreatedAt   QuestionSort = "created_at"
+	SortByAnswerCount QuestionSort = "answers"
+)
+
+// QuestionListOptions describes a page of GET /questions. When Cursor is set
+// it takes precedence over Offset.
+type QuestionListOptions struct {
+	Limit         int
+	Offset        int
+	Cursor        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	AuthorID      string
+	Answered      *bool
+	Tags          []string
+	TagMode       TagMode
+	Sort          QuestionSort
+	Desc          bool
+}
+
+type AnswerSort string
+
+const (
+	SortAnswersByScore  AnswerSort = "score"
+	SortAnswersByNewest AnswerSort = "newest"
+	SortAnswersByOldest AnswerSort = "oldest"
+)
+
+// QuestionDetailOptions controls how GET /questions/:id embeds answers.
+type QuestionDetailOptions struct {
+	AnswerSort      AnswerSort
+	IncludeComments bool
+}
+
+type QuestionPage struct {
+	Items      []models.Question `json:"items"`
+	Total      int64             `json:"total"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// pageCursor is the keyset position after the last item of a page. It is
+// serialized as base64 JSON so clients treat it as an opaque token.
+type pageCursor struct {
+	Sort      QuestionSort `json:"s"`
+	Desc      bool         `json:"d"`
+	CreatedAt time.Time    `json:"c,omitempty"`
+	Count     int64        `json:"n,omitempty"`
+	ID        uint         `json:"i"`
+}
+
+func encodeCursor(cur pageCursor) string {
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string, opts QuestionListOptions) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cur pageCursor
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cur.Sort != opts.Sort || cur.Desc != opts.Desc || cur.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cur, nil
+}
+
+func (o *QuestionListOptions) normalize() {
+	if o.Limit <= 0 {
+		o.Limit = DefaultPageLimit
+	}
+	if o.Limit > MaxPageLimit {
+		o.Limit = MaxPageLimit
+	}
+	if o.Offset < 0 {
+		o.Offset = 0
+	}
+	if o.Sort == "" {
+		o.Sort = SortByCreatedAt
+	}
+}
@@ -2,16 +2,43 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-func (r *Repository) CreateAnswer(ctx context.Context, answer *models.Answer) error {
-	result := r.db.WithContext(ctx).Create(answer)
-	if result.Error != nil {
-		slog.ErrorContext(ctx, "Failed to create answer", "error", result.Error)
-		return result.Error
+// CreateAnswer adds an answer to an open question. ifMatch is checked
+// against the question version.
+func (r *Repository) CreateAnswer(ctx context.Context, answer *models.Answer, ifMatch []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question models.Question
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Select("id", "status", "version").
+			First(&question, answer.QuestionID).Error
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(ifMatch, question.Version); err != nil {
+			return err
+		}
+		if !question.AcceptsAnswers() {
+			return ErrQuestionClosed
+		}
+
+		if err := tx.Create(answer).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, "answer.create", "answer", answer.ID, nil, answer); err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create answer", "error", err)
+		return translateError(err)
 	}
 	return nil
 }
@@ -20,19 +47,112 @@ func (r *Repository) GetAnswer(ctx context.Context, id uint) (*models.Answer, er
 	var answer models.Answer
 
 	result := r.db.WithContext(ctx).Where("id = ?", id).First(&answer)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
 	if result.Error != nil {
 		slog.ErrorContext(ctx, "Failed to get answer", "id", id, "error", result.Error)
-		return nil, result.Error
+		return nil, translateError(result.Error)
 	}
 
 	return &answer, nil
 }
 
-func (r *Repository) DeleteAnswer(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&models.Answer{}, id)
-	if result.Error != nil {
-		slog.ErrorContext(ctx, "Failed to delete answer", "id", id, "error", result.Error)
-		return result.Error
+// UpdateAnswer replaces the answer text and records the previous text as a
+// revision in the same transaction.
+func (r *Repository) UpdateAnswer(ctx context.Context, id uint, upd TextUpdate) (*models.Answer, error) {
+	var answer models.Answer
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&answer, id).Error; err != nil {
+			return err
+		}
+
+		if err := checkVersion(upd.IfMatch, answer.Version); err != nil {
+			return err
+		}
+		if err := checkNotLocked(tx, answer.QuestionID); err != nil {
+			return err
+		}
+
+		if answer.Text == upd.Text {
+			return nil
+		}
+
+		revision := models.AnswerRevision{
+			AnswerID:     answer.ID,
+			Editor:       upd.Editor,
+			PreviousText: answer.Text,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		before := answer
+		answer.Text = upd.Text
+		answer.Version++
+		err := tx.Model(&answer).Updates(map[string]interface{}{
+			"text":    upd.Text,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(tx, "answer.update", "answer", answer.ID, before, answer); err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if errors.Is(err, ErrVersionMismatch) {
+		return nil, err
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update answer", "id", id, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &answer, nil
+}
+
+func (r *Repository) DeleteAnswer(ctx context.Context, id uint, ifMatch []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var answer models.Answer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&answer, id).Error; err != nil {
+			return err
+		}
+
+		if err := checkVersion(ifMatch, answer.Version); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&answer).Error; err != nil {
+			return err
+		}
+
+		// A trashed answer can't stay accepted, mirroring ON DELETE SET NULL.
+		err := tx.Model(&models.Question{}).
+			Where("id = ? AND accepted_answer_id = ?", answer.QuestionID, answer.ID).
+			Update("accepted_answer_id", nil).Error
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(tx, "answer.delete", "answer", answer.ID, answer, nil); err != nil {
+			return err
+		}
+		return touchQuestion(tx, answer.QuestionID)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	if errors.Is(err, ErrVersionMismatch) {
+		return err
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete answer", "id", id, "error", err)
+		return translateError(err)
 	}
 	return nil
 }
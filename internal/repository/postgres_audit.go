@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/audit"
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type AuditListOptions struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+type AuditPage struct {
+	Items []models.AuditEvent `json:"items"`
+	Total int64               `json:"total"`
+}
+
+// recordAudit appends an audit event inside the caller's transaction, so the
+// event is stored if and only if the change is. Actor, request ID and client
+// IP come from the audit metadata in the transaction context. Pass a nil
+// before for creates and a nil after for deletes.
+func recordAudit(tx *gorm.DB, action, entityType string, entityID interface{}, before, after interface{}) error {
+	meta := audit.FromContext(tx.Statement.Context)
+
+	event := models.AuditEvent{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Actor:      optionalString(meta.Actor),
+		RequestID:  optionalString(meta.RequestID),
+		ClientIP:   optionalString(meta.ClientIP),
+	}
+
+	var err error
+	if before != nil {
+		if event.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("marshal audit snapshot: %w", err)
+		}
+	}
+	if after != nil {
+		if event.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("marshal audit snapshot: %w", err)
+		}
+	}
+
+	return tx.Create(&event).Error
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (r *Repository) GetAuditEvents(ctx context.Context, opts AuditListOptions) (*AuditPage, error) {
+	if opts.Limit <= 0 || opts.Limit > MaxPageLimit {
+		opts.Limit = DefaultPageLimit
+	}
+
+	base := r.db.WithContext(ctx).Model(&models.AuditEvent{})
+	if opts.Actor != "" {
+		base = base.Where("actor = ?", opts.Actor)
+	}
+	if opts.Action != "" {
+		base = base.Where("action = ?", opts.Action)
+	}
+	if opts.EntityType != "" {
+		base = base.Where("entity_type = ?", opts.EntityType)
+	}
+	if opts.EntityID != "" {
+		base = base.Where("entity_id = ?", opts.EntityID)
+	}
+	if opts.From != nil {
+		base = base.Where("created_at >= ?", *opts.From)
+	}
+	if opts.To != nil {
+		base = base.Where("created_at < ?", *opts.To)
+	}
+	base = base.Session(&gorm.Session{})
+
+	page := &AuditPage{Items: []models.AuditEvent{}}
+	if err := base.Count(&page.Total).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to count audit events", "error", err)
+		return nil, translateError(err)
+	}
+
+	err := base.Order("id DESC").Limit(opts.Limit).Offset(opts.Offset).Find(&page.Items).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get audit events", "error", err)
+		return nil, translateError(err)
+	}
+
+	return page, nil
+}
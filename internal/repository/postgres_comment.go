@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func orderComments(db *gorm.DB) *gorm.DB {
+	return db.Order("comments.created_at ASC, comments.id ASC")
+}
+
+// CreateComment stores a comment on a question or an answer. Exactly one of
+// comment.QuestionID and comment.AnswerID must be set. Comments are embedded
+// in the question representation, so the question version is bumped. ifMatch
+// is checked against the version of the commented question or answer.
+func (r *Repository) CreateComment(ctx context.Context, comment *models.Comment, ifMatch []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		questionID, version, err := commentTarget(tx, comment)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(ifMatch, version); err != nil {
+			return err
+		}
+		if err := checkNotLocked(tx, questionID); err != nil {
+			return err
+		}
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, "comment.create", "comment", comment.ID, nil, comment); err != nil {
+			return err
+		}
+		return touchQuestion(tx, questionID)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create comment", "error", err)
+		return translateError(err)
+	}
+	return nil
+}
+
+// commentTarget locks the commented question or answer and returns the ID
+// of the question it belongs to and the version of the commented entity.
+func commentTarget(tx *gorm.DB, comment *models.Comment) (questionID, version uint, err error) {
+	switch {
+	case comment.QuestionID != nil && comment.AnswerID == nil:
+		var question models.Question
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id", "version").First(&question, *comment.QuestionID).Error
+		return question.ID, question.Version, err
+	case comment.AnswerID != nil && comment.QuestionID == nil:
+		var answer models.Answer
+		err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id", "question_id", "version").First(&answer, *comment.AnswerID).Error
+		return answer.QuestionID, answer.Version, err
+	default:
+		return 0, 0, errors.New("comment must reference either a question or an answer")
+	}
+}
+
+func (r *Repository) GetQuestionComments(ctx context.Context, questionID uint) ([]models.Comment, error) {
+	return r.getComments(ctx, &models.Question{}, "question_id", questionID)
+}
+
+func (r *Repository) GetAnswerComments(ctx context.Context, answerID uint) ([]models.Comment, error) {
+	return r.getComments(ctx, &models.Answer{}, "answer_id", answerID)
+}
+
+// getComments lists the comments of a parent entity. It returns ErrNotFound
+// when the parent does not exist.
+func (r *Repository) getComments(ctx context.Context, parent interface{}, column string, parentID uint) ([]models.Comment, error) {
+	db := r.db.WithContext(ctx)
+
+	var count int64
+	if err := db.Model(parent).Where("id = ?", parentID).Count(&count).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to check comment parent", column, parentID, "error", err)
+		return nil, translateError(err)
+	}
+	if count == 0 {
+		return nil, ErrNotFound
+	}
+
+	comments := []models.Comment{}
+	if err := orderComments(db.Where(column+" = ?", parentID)).Find(&comments).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to get comments", column, parentID, "error", err)
+		return nil, translateError(err)
+	}
+
+	return comments, nil
+}
+
+func (r *Repository) GetComment(ctx context.Context, id uint) (*models.Comment, error) {
+	var comment models.Comment
+
+	result := r.db.WithContext(ctx).First(&comment, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get comment", "id", id, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+
+	return &comment, nil
+}
+
+// DeleteComment removes a comment. It also works while the commented question
+// or answer is in the trash, so authors and moderators can still clean up.
+func (r *Repository) DeleteComment(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, id).Error; err != nil {
+			return err
+		}
+
+		questionID, _, err := commentTarget(tx.Unscoped(), &comment)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&comment).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, "comment.delete", "comment", comment.ID, comment, nil); err != nil {
+			return err
+		}
+		return touchQuestion(tx, questionID)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete comment", "id", id, "error", err)
+		return translateError(err)
+	}
+	return nil
+}
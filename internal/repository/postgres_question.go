@@ -2,45 +2,340 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// CreateQuestion stores the question and its tags. Only the names of
+// question.Tags are used; tags are created on first use.
 func (r *Repository) CreateQuestion(ctx context.Context, question *models.Question) error {
-	result := r.db.WithContext(ctx).Create(question)
-	if result.Error != nil {
-		slog.ErrorContext(ctx, "Failed to create question", "error", result.Error)
-		return result.Error
+	names := make([]string, 0, len(question.Tags))
+	for _, tag := range question.Tags {
+		names = append(names, tag.Name)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(question).Error; err != nil {
+			return err
+		}
+
+		tags, err := attachTags(tx, question.ID, names)
+		if err != nil {
+			return err
+		}
+		question.Tags = tags
+		return recordAudit(tx, "question.create", "question", question.ID, nil, question)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to create question", "error", err)
+		return translateError(err)
 	}
 	return nil
 }
 
-func (r *Repository) GetQuestions(ctx context.Context) ([]models.Question, error) {
+// answerCountExpr is used both as a selected column and as a keyset key, so
+// sorting and cursor comparison always agree.
+const answerCountExpr = "(SELECT COUNT(*) FROM answers WHERE answers.question_id = questions.id AND answers.deleted_at IS NULL)"
+
+func (r *Repository) GetQuestions(ctx context.Context, opts QuestionListOptions) (*QuestionPage, error) {
+	opts.normalize()
+
+	var cursor *pageCursor
+	if opts.Cursor != "" {
+		var err error
+		cursor, err = decodeCursor(opts.Cursor, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	base := r.db.WithContext(ctx).Model(&models.Question{})
+	if opts.CreatedAfter != nil {
+		base = base.Where("questions.created_at >= ?", *opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != nil {
+		base = base.Where("questions.created_at < ?", *opts.CreatedBefore)
+	}
+	if opts.AuthorID != "" {
+		base = base.Where("questions.author_id = ?", opts.AuthorID)
+	}
+	if len(opts.Tags) > 0 {
+		base = tagFilter(base, opts.Tags, opts.TagMode)
+	}
+	if opts.Answered != nil {
+		if *opts.Answered {
+			base = base.Where("questions.accepted_answer_id IS NOT NULL")
+		} else {
+			base = base.Where("questions.accepted_answer_id IS NULL")
+		}
+	}
+	base = base.Session(&gorm.Session{})
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to count questions", "error", err)
+		return nil, translateError(err)
+	}
+
+	sortKey := "questions.created_at"
+	if opts.Sort == SortByAnswerCount {
+		sortKey = answerCountExpr
+	}
+	direction, cmp := "ASC", ">"
+	if opts.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	query := base.Select("questions.*, " + answerCountExpr + " AS answer_count").
+		Order(sortKey + " " + direction).
+		Order("questions.id " + direction).
+		Limit(opts.Limit + 1)
+
+	if cursor != nil {
+		var key interface{} = cursor.CreatedAt
+		if opts.Sort == SortByAnswerCount {
+			key = cursor.Count
+		}
+		query = query.Where("("+sortKey+", questions.id) "+cmp+" (?, ?)", key, cursor.ID)
+	} else if opts.Offset > 0 {
+		query = query.Offset(opts.Offset)
+	}
+
 	var questions []models.Question
-	result := r.db.WithContext(ctx).Find(&questions)
-	if result.Error != nil {
-		slog.ErrorContext(ctx, "Failed to get questions", "error", result.Error)
-		return nil, result.Error
+	if err := query.Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") }).Find(&questions).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to get questions", "error", err)
+		return nil, translateError(err)
+	}
+
+	page := &QuestionPage{Items: questions, Total: total}
+	if len(questions) > opts.Limit {
+		page.Items = questions[:opts.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(pageCursor{
+			Sort:      opts.Sort,
+			Desc:      opts.Desc,
+			CreatedAt: last.CreatedAt,
+			Count:     last.AnswerCount,
+			ID:        last.ID,
+		})
 	}
-	return questions, nil
+	if page.Items == nil {
+		page.Items = []models.Question{}
+	}
+
+	return page, nil
 }
 
-func (r *Repository) GetQuestion(ctx context.Context, id uint) (*models.Question, error) {
+func (r *Repository) GetQuestion(ctx context.Context, id uint, opts QuestionDetailOptions) (*models.Question, error) {
+	answerOrder := "answers.created_at ASC, answers.id ASC"
+	switch opts.AnswerSort {
+	case SortAnswersByScore:
+		answerOrder = "answers.score DESC, answers.created_at ASC, answers.id ASC"
+	case SortAnswersByNewest:
+		answerOrder = "answers.created_at DESC, answers.id DESC"
+	}
+
+	query := r.db.WithContext(ctx).
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order(answerOrder) }).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") })
+	if opts.IncludeComments {
+		query = query.
+			Preload("Comments", orderComments).
+			Preload("Answers.Comments", orderComments)
+	}
+
 	var question models.Question
-	result := r.db.WithContext(ctx).Preload("Answers").First(&question, id)
+	result := query.First(&question, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
 	if result.Error != nil {
 		slog.ErrorContext(ctx, "Failed to get question", "id", id, "error", result.Error)
-		return nil, result.Error
+		return nil, translateError(result.Error)
 	}
+	question.AnswerCount = int64(len(question.Answers))
+	markAcceptedAnswer(&question)
 	return &question, nil
 }
 
-func (r *Repository) DeleteQuestion(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&models.Question{}, id)
-	if result.Error != nil {
-		slog.ErrorContext(ctx, "Failed to delete question", "id", id, "error", result.Error)
-		return result.Error
+// markAcceptedAnswer flags the accepted answer and moves it to the front,
+// keeping the requested order for the rest.
+func markAcceptedAnswer(question *models.Question) {
+	if question.AcceptedAnswerID == nil {
+		return
+	}
+	for i := range question.Answers {
+		if question.Answers[i].ID != *question.AcceptedAnswerID {
+			continue
+		}
+		accepted := question.Answers[i]
+		accepted.Accepted = true
+		copy(question.Answers[1:i+1], question.Answers[:i])
+		question.Answers[0] = accepted
+		return
+	}
+}
+
+// SetAcceptedAnswer marks answerID as the accepted answer of the question, or
+// clears it when accept is false. The answer must belong to the question, and
+// can only be un-accepted while it is the accepted one; otherwise
+// ErrNotAccepted is returned. Locked questions return ErrQuestionLocked.
+func (r *Repository) SetAcceptedAnswer(ctx context.Context, questionID, answerID uint, accept bool, ifMatch []uint) (*models.Question, error) {
+	var question models.Question
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, questionID).Error; err != nil {
+			return err
+		}
+
+		if err := checkVersion(ifMatch, question.Version); err != nil {
+			return err
+		}
+		if question.Locked() {
+			return ErrQuestionLocked
+		}
+
+		var accepted *uint
+		if accept {
+			var count int64
+			err := tx.Model(&models.Answer{}).
+				Where("id = ? AND question_id = ?", answerID, questionID).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			accepted = &answerID
+		} else if question.AcceptedAnswerID == nil || *question.AcceptedAnswerID != answerID {
+			return ErrNotAccepted
+		}
+
+		before := question
+		question.AcceptedAnswerID = accepted
+		question.Version++
+		err := tx.Model(&question).Updates(map[string]interface{}{
+			"accepted_answer_id": accepted,
+			"version":            gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		action := "question.accept"
+		if !accept {
+			action = "question.unaccept"
+		}
+		return recordAudit(tx, action, "question", question.ID, before, question)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to set accepted answer", "question_id", questionID, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &question, nil
+}
+
+// UpdateQuestion replaces the question text and records the previous text as
+// a revision in the same transaction.
+func (r *Repository) UpdateQuestion(ctx context.Context, id uint, upd TextUpdate) (*models.Question, error) {
+	var question models.Question
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, id).Error; err != nil {
+			return err
+		}
+
+		if err := checkVersion(upd.IfMatch, question.Version); err != nil {
+			return err
+		}
+		if question.Locked() {
+			return ErrQuestionLocked
+		}
+
+		if question.Text == upd.Text {
+			return nil
+		}
+
+		revision := models.QuestionRevision{
+			QuestionID:   question.ID,
+			Editor:       upd.Editor,
+			PreviousText: question.Text,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		before := question
+		question.Text = upd.Text
+		question.Version++
+		err := tx.Model(&question).Updates(map[string]interface{}{
+			"text":    upd.Text,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, "question.update", "question", question.ID, before, question)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if errors.Is(err, ErrVersionMismatch) {
+		return nil, err
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to update question", "id", id, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &question, nil
+}
+
+// DeleteQuestion moves the question and its answers to the trash. They are
+// removed for good by PurgeDeleted once the retention period has passed.
+func (r *Repository) DeleteQuestion(ctx context.Context, id uint, ifMatch []uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var question models.Question
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, id).Error; err != nil {
+			return err
+		}
+
+		if err := checkVersion(ifMatch, question.Version); err != nil {
+			return err
+		}
+
+		// Answers share the question's timestamp so RestoreQuestion can tell
+		// them apart from answers that were deleted on their own earlier.
+		now := time.Now()
+		err := tx.Model(&models.Answer{}).Where("question_id = ?", id).Update("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&question).Updates(map[string]interface{}{
+			"deleted_at": now,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, "question.delete", "question", question.ID, question, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	if errors.Is(err, ErrVersionMismatch) {
+		return err
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to delete question", "id", id, "error", err)
+		return translateError(err)
 	}
 	return nil
 }
@@ -50,7 +345,7 @@ func (r *Repository) QuestionExists(ctx context.Context, id uint) (bool, error)
 	result := r.db.WithContext(ctx).Model(&models.Question{}).Where("id = ?", id).Count(&count)
 	if result.Error != nil {
 		slog.ErrorContext(ctx, "Failed to check question existence", "id", id, "error", result.Error)
-		return false, result.Error
+		return false, translateError(result.Error)
 	}
 	return count > 0, nil
 }
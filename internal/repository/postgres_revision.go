@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// TextUpdate is an edit of a question or answer body. When IfMatch is not
+// nil the edit only applies if the current version is one of its values.
+type TextUpdate struct {
+	Text    string
+	Editor  string
+	IfMatch []uint
+}
+
+// GetQuestionText returns the live question text without loading answers,
+// tags or comments.
+func (r *Repository) GetQuestionText(ctx context.Context, id uint) (string, error) {
+	var question models.Question
+	result := r.db.WithContext(ctx).Select("id", "text").First(&question, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return "", ErrNotFound
+	}
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get question text", "id", id, "error", result.Error)
+		return "", translateError(result.Error)
+	}
+	return question.Text, nil
+}
+
+func (r *Repository) GetQuestionRevisions(ctx context.Context, questionID uint) ([]models.QuestionRevision, error) {
+	revisions := []models.QuestionRevision{}
+	result := r.db.WithContext(ctx).
+		Where("question_id = ?", questionID).
+		Order("id DESC").
+		Find(&revisions)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get question revisions", "question_id", questionID, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+	return revisions, nil
+}
+
+func (r *Repository) GetQuestionRevision(ctx context.Context, questionID, revisionID uint) (*models.QuestionRevision, error) {
+	var revision models.QuestionRevision
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND question_id = ?", revisionID, questionID).
+		First(&revision)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get question revision", "question_id", questionID, "revision_id", revisionID, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+	return &revision, nil
+}
+
+func (r *Repository) GetAnswerRevisions(ctx context.Context, answerID uint) ([]models.AnswerRevision, error) {
+	revisions := []models.AnswerRevision{}
+	result := r.db.WithContext(ctx).
+		Where("answer_id = ?", answerID).
+		Order("id DESC").
+		Find(&revisions)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get answer revisions", "answer_id", answerID, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+	return revisions, nil
+}
+
+func (r *Repository) GetAnswerRevision(ctx context.Context, answerID, revisionID uint) (*models.AnswerRevision, error) {
+	var revision models.AnswerRevision
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND answer_id = ?", revisionID, answerID).
+		First(&revision)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get answer revision", "answer_id", answerID, "revision_id", revisionID, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+	return &revision, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"strings"
+)
+
+type SearchType string
+
+const (
+	SearchAll       SearchType = ""
+	SearchQuestions SearchType = "question"
+	SearchAnswers   SearchType = "answer"
+)
+
+type SearchOptions struct {
+	Query  string
+	Type   SearchType
+	Limit  int
+	Offset int
+}
+
+// SearchHit is a ranked match. Snippet is HTML-escaped text in which the
+// matched terms are wrapped in <mark> tags.
+type SearchHit struct {
+	Type       SearchType `json:"type"`
+	ID         uint       `json:"id"`
+	QuestionID uint       `json:"question_id"`
+	Rank       float64    `json:"rank"`
+	Snippet    string     `json:"snippet"`
+}
+
+type SearchResult struct {
+	Items []SearchHit `json:"items"`
+	Total int64       `json:"total"`
+}
+
+type searchRow struct {
+	SearchHit
+	Total int64
+}
+
+// ts_headline marks matches with private-use sentinels rather than <mark>,
+// so that markup typed by users can't pass for a highlight once escaped.
+// Sentinels already in the text are stripped before the headline is built.
+const (
+	markStart = "\uE000"
+	markStop  = "\uE001"
+
+	headlineOptions = `StartSel="` + markStart + `", StopSel="` + markStop + `", MaxWords=35, MinWords=15, MaxFragments=2`
+)
+
+// searchSQL ranks questions and answers together and only builds headlines
+// for the requested page, since ts_headline re-parses the whole document.
+const searchSQL = `
+WITH query AS (
+    SELECT websearch_to_tsquery('russian', @q) || websearch_to_tsquery('english', @q) AS tsq
+),
+hits AS (
+    SELECT matches.*, COUNT(*) OVER () AS total
+    FROM (
+        SELECT 'question' AS type, q.id, q.id AS question_id, q.text,
+               ts_rank(q.search_vector, query.tsq) AS rank
+        FROM questions q, query
+        WHERE @type IN ('', 'question') AND q.deleted_at IS NULL AND q.search_vector @@ query.tsq
+        UNION ALL
+        SELECT 'answer' AS type, a.id, a.question_id, a.text,
+               ts_rank(a.search_vector, query.tsq) AS rank
+        FROM answers a, query
+        WHERE @type IN ('', 'answer') AND a.deleted_at IS NULL AND a.search_vector @@ query.tsq
+    ) matches
+    ORDER BY rank DESC, type DESC, id
+    LIMIT @limit OFFSET @offset
+)
+SELECT hits.type, hits.id, hits.question_id, hits.rank, hits.total,
+       ts_headline('russian', translate(hits.text, @sentinels, ''), query.tsq, @headline) AS snippet
+FROM hits, query
+ORDER BY hits.rank DESC, hits.type DESC, hits.id`
+
+func (r *Repository) Search(ctx context.Context, opts SearchOptions) (*SearchResult, error) {
+	if opts.Limit <= 0 || opts.Limit > MaxPageLimit {
+		opts.Limit = DefaultPageLimit
+	}
+
+	var rows []searchRow
+	result := r.db.WithContext(ctx).Raw(searchSQL, map[string]interface{}{
+		"q":         opts.Query,
+		"type":      string(opts.Type),
+		"limit":     opts.Limit,
+		"offset":    opts.Offset,
+		"headline":  headlineOptions,
+		"sentinels": markStart + markStop,
+	}).Scan(&rows)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to search", "query", opts.Query, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+
+	res := &SearchResult{Items: make([]SearchHit, 0, len(rows))}
+	for _, row := range rows {
+		hit := row.SearchHit
+		hit.Snippet = escapeSnippet(hit.Snippet)
+		res.Items = append(res.Items, hit)
+		res.Total = row.Total
+	}
+
+	return res, nil
+}
+
+var snippetMarker = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+// escapeSnippet escapes user text so snippets are safe to render as HTML and
+// turns the ts_headline sentinels into highlight tags.
+func escapeSnippet(s string) string {
+	return snippetMarker.Replace(html.EscapeString(s))
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeSnippet(t *testing.T) {
+	got := escapeSnippet("a <mark>fake</mark> & " + markStart + "real" + markStop + " <b>")
+
+	assert.Equal(t, "a &lt;mark&gt;fake&lt;/mark&gt; &amp; <mark>real</mark> &lt;b&gt;", got)
+}
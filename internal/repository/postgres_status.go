@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// statusTransitions lists the statuses a question may move to from each
+// status. Moving to the current status is not a transition.
+var statusTransitions = map[models.QuestionStatus][]models.QuestionStatus{
+	models.QuestionOpen:      {models.QuestionClosed, models.QuestionLocked, models.QuestionDuplicate},
+	models.QuestionClosed:    {models.QuestionOpen, models.QuestionLocked, models.QuestionDuplicate},
+	models.QuestionDuplicate: {models.QuestionOpen, models.QuestionClosed, models.QuestionLocked},
+	models.QuestionLocked:    {models.QuestionOpen, models.QuestionClosed},
+}
+
+// StatusChange moves a question to Status. Reason is stored as the close
+// reason and DuplicateOf is required for the duplicate status.
+type StatusChange struct {
+	Status      models.QuestionStatus
+	Reason      string
+	DuplicateOf *uint
+	ChangedBy   string
+	IfMatch     []uint
+}
+
+// ChangeQuestionStatus applies a lifecycle transition and records it in
+// question_status_changes in the same transaction. It returns
+// ErrInvalidTransition when the move is not allowed from the current status.
+func (r *Repository) ChangeQuestionStatus(ctx context.Context, id uint, change StatusChange) (*models.Question, error) {
+	var question models.Question
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&question, id).Error; err != nil {
+			return err
+		}
+
+		if err := checkVersion(change.IfMatch, question.Version); err != nil {
+			return err
+		}
+
+		before := question
+		from := question.Status
+		if !slices.Contains(statusTransitions[from], change.Status) {
+			return ErrInvalidTransition
+		}
+
+		var reason *string
+		if change.Reason != "" {
+			reason = &change.Reason
+		}
+		var duplicateOf *uint
+		if change.Status == models.QuestionDuplicate {
+			duplicateOf = change.DuplicateOf
+		}
+
+		err := tx.Model(&question).Updates(map[string]interface{}{
+			"status":       change.Status,
+			"close_reason": reason,
+			"duplicate_of": duplicateOf,
+			"version":      gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		record := models.QuestionStatusChange{
+			QuestionID:  id,
+			FromStatus:  from,
+			ToStatus:    change.Status,
+			Reason:      reason,
+			DuplicateOf: duplicateOf,
+			ChangedBy:   change.ChangedBy,
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+
+		question.Status = change.Status
+		question.CloseReason = reason
+		question.DuplicateOf = duplicateOf
+		question.Version++
+		return recordAudit(tx, "question.status", "question", question.ID, before, question)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if errors.Is(err, ErrVersionMismatch) || errors.Is(err, ErrInvalidTransition) {
+		return nil, err
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to change question status", "id", id, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &question, nil
+}
+
+func (r *Repository) GetQuestionStatusHistory(ctx context.Context, questionID uint) ([]models.QuestionStatusChange, error) {
+	changes := []models.QuestionStatusChange{}
+	result := r.db.WithContext(ctx).
+		Where("question_id = ?", questionID).
+		Order("id DESC").
+		Find(&changes)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get question status history", "question_id", questionID, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+	return changes, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagMode string
+
+const (
+	TagModeAll TagMode = "all"
+	TagModeAny TagMode = "any"
+)
+
+type TagListOptions struct {
+	Limit  int
+	Offset int
+}
+
+type TagPage struct {
+	Items []models.Tag `json:"items"`
+	Total int64        `json:"total"`
+}
+
+// attachTags creates missing tags and links them to the question. Names must
+// already be normalised.
+func attachTags(tx *gorm.DB, questionID uint, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	newTags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, models.Tag{Name: name})
+	}
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoNothing: true,
+	}).Create(&newTags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := tx.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	links := make([]map[string]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		links = append(links, map[string]interface{}{"question_id": questionID, "tag_id": tag.ID})
+	}
+	if err := tx.Table("question_tags").Clauses(clause.OnConflict{DoNothing: true}).Create(links).Error; err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// tagFilter restricts a questions query to the given tag names. In all mode a
+// question must carry every tag, in any mode at least one of them.
+func tagFilter(db *gorm.DB, names []string, mode TagMode) *gorm.DB {
+	sub := db.Session(&gorm.Session{NewDB: true}).
+		Table("question_tags").
+		Select("question_tags.question_id").
+		Joins("JOIN tags ON tags.id = question_tags.tag_id").
+		Where("tags.name IN ?", names)
+
+	if mode != TagModeAny {
+		sub = sub.Group("question_tags.question_id").
+			Having("COUNT(DISTINCT tags.id) = ?", len(names))
+	}
+
+	return db.Where("questions.id IN (?)", sub)
+}
+
+func (r *Repository) GetTags(ctx context.Context, opts TagListOptions) (*TagPage, error) {
+	if opts.Limit <= 0 || opts.Limit > MaxPageLimit {
+		opts.Limit = DefaultPageLimit
+	}
+
+	page := &TagPage{Items: []models.Tag{}}
+	if err := r.db.WithContext(ctx).Model(&models.Tag{}).Count(&page.Total).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to count tags", "error", err)
+		return nil, translateError(err)
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&models.Tag{}).
+		Select("tags.*, COUNT(questions.id) AS usage_count").
+		Joins("LEFT JOIN question_tags ON question_tags.tag_id = tags.id").
+		Joins("LEFT JOIN questions ON questions.id = question_tags.question_id AND questions.deleted_at IS NULL").
+		Group("tags.id").
+		Order("usage_count DESC, tags.name ASC").
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		Find(&page.Items)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get tags", "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+
+	return page, nil
+}
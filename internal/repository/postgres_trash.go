@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrashListOptions struct {
+	AuthorID string
+	Limit    int
+	Offset   int
+}
+
+// GetTrash lists soft-deleted questions, most recently deleted first. An
+// empty AuthorID lists the whole trash.
+func (r *Repository) GetTrash(ctx context.Context, opts TrashListOptions) (*QuestionPage, error) {
+	if opts.Limit <= 0 || opts.Limit > MaxPageLimit {
+		opts.Limit = DefaultPageLimit
+	}
+
+	base := r.db.WithContext(ctx).Unscoped().Model(&models.Question{}).Where("questions.deleted_at IS NOT NULL")
+	if opts.AuthorID != "" {
+		base = base.Where("questions.author_id = ?", opts.AuthorID)
+	}
+
+	page := &QuestionPage{Items: []models.Question{}}
+	if err := base.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to count trash", "error", err)
+		return nil, translateError(err)
+	}
+
+	err := base.
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("tags.name") }).
+		Order("questions.deleted_at DESC, questions.id DESC").
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		Find(&page.Items).Error
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get trash", "error", err)
+		return nil, translateError(err)
+	}
+
+	return page, nil
+}
+
+// GetDeletedQuestion returns a question from the trash. It returns
+// ErrNotFound for live questions as well as missing ones.
+func (r *Repository) GetDeletedQuestion(ctx context.Context, id uint) (*models.Question, error) {
+	var question models.Question
+
+	result := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&question, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get deleted question", "id", id, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+
+	return &question, nil
+}
+
+// RestoreQuestion takes a question out of the trash together with the answers
+// that were deleted with it.
+func (r *Repository) RestoreQuestion(ctx context.Context, id uint, ifMatch []uint) (*models.Question, error) {
+	var question models.Question
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			First(&question, id).Error
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(ifMatch, question.Version); err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&models.Answer{}).
+			Where("question_id = ? AND deleted_at = ?", id, question.DeletedAt.Time).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&question).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		before := question
+		question.DeletedAt = gorm.DeletedAt{}
+		question.Version++
+		return recordAudit(tx, "question.restore", "question", question.ID, before, question)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to restore question", "id", id, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &question, nil
+}
+
+type purgeSummary struct {
+	Before      time.Time `json:"before"`
+	AnswerIDs   []uint    `json:"answer_ids"`
+	QuestionIDs []uint    `json:"question_ids"`
+}
+
+// PurgeDeleted permanently removes questions and answers that were deleted
+// before the cutoff. Comments, votes, revisions and tag links go with them
+// through ON DELETE CASCADE. A non-empty purge is audited as one event
+// listing the removed IDs.
+func (r *Repository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var answers []models.Answer
+		result := tx.Unscoped().
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "question_id"}}}).
+			Where("deleted_at < ?", before).
+			Delete(&answers)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var questions []models.Question
+		result = tx.Unscoped().
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("deleted_at < ?", before).
+			Delete(&questions)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		purged = int64(len(answers) + len(questions))
+		if purged == 0 {
+			return nil
+		}
+
+		// Answers trashed on their own leave a live question behind, whose
+		// representation no longer matches the ETags clients hold.
+		var touched []uint
+		for _, a := range answers {
+			if !slices.Contains(touched, a.QuestionID) {
+				touched = append(touched, a.QuestionID)
+			}
+		}
+		if err := touchQuestion(tx, touched...); err != nil {
+			return err
+		}
+
+		summary := purgeSummary{Before: before, AnswerIDs: []uint{}, QuestionIDs: []uint{}}
+		for _, a := range answers {
+			summary.AnswerIDs = append(summary.AnswerIDs, a.ID)
+		}
+		for _, q := range questions {
+			summary.QuestionIDs = append(summary.QuestionIDs, q.ID)
+		}
+		return recordAudit(tx, "trash.purge", "trash", before.UTC().Format(time.RFC3339), nil, summary)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to purge deleted content", "before", before, "error", err)
+		return 0, translateError(err)
+	}
+
+	return purged, nil
+}
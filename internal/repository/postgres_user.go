@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnswerListOptions struct {
+	Limit  int
+	Offset int
+}
+
+type AnswerPage struct {
+	Items []models.Answer `json:"items"`
+	Total int64           `json:"total"`
+}
+
+// EnsureUser inserts the user unless a row with the same ID already exists,
+// in which case only the display name is brought up to date. If the requested
+// handle belongs to someone else, the user ID is used as the handle instead,
+// since IDs are unique too. If that handle is taken as well, it returns
+// ErrConflict and no user exists.
+func (r *Repository) EnsureUser(ctx context.Context, user *models.User) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return recordAudit(tx, "user.create", "user", user.ID, nil, user)
+		}
+
+		var existing models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", user.ID).First(&existing).Error
+		if err == nil {
+			return updateDisplayName(tx, existing, user.DisplayName)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		user.Handle = user.ID
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return recordAudit(tx, "user.create", "user", user.ID, nil, user)
+		}
+
+		// Either the user was registered concurrently, or another user's
+		// handle equals this ID and the user can't be created at all.
+		exists, err := userExists(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: handle %q is taken", ErrConflict, user.Handle)
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to ensure user", "id", user.ID, "error", err)
+		return translateError(err)
+	}
+	return nil
+}
+
+// updateDisplayName stores displayName on an existing user if it changed.
+func updateDisplayName(tx *gorm.DB, user models.User, displayName string) error {
+	if user.DisplayName == displayName {
+		return nil
+	}
+	if err := tx.Model(&user).Update("display_name", displayName).Error; err != nil {
+		return err
+	}
+	before := user
+	user.DisplayName = displayName
+	return recordAudit(tx, "user.update", "user", user.ID, before, user)
+}
+
+func userExists(tx *gorm.DB, id string) (bool, error) {
+	var count int64
+	err := tx.Model(&models.User{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *Repository) GetUser(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&user)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get user", "id", id, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+	return &user, nil
+}
+
+// SetUserRole replaces the user's role and returns the updated user.
+func (r *Repository) SetUserRole(ctx context.Context, id string, role models.Role) (*models.User, error) {
+	var user models.User
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&user).Error; err != nil {
+			return err
+		}
+		if user.Role == role {
+			return nil
+		}
+		if err := tx.Model(&user).Update("role", role).Error; err != nil {
+			return err
+		}
+		before := user
+		user.Role = role
+		return recordAudit(tx, "user.role", "user", user.ID, before, user)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to set user role", "id", id, "role", role, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &user, nil
+}
+
+func (r *Repository) GetUserAnswers(ctx context.Context, userID string, opts AnswerListOptions) (*AnswerPage, error) {
+	if opts.Limit <= 0 || opts.Limit > MaxPageLimit {
+		opts.Limit = DefaultPageLimit
+	}
+
+	base := r.db.WithContext(ctx).Model(&models.Answer{}).
+		Where("user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	page := &AnswerPage{Items: []models.Answer{}}
+	if err := base.Count(&page.Total).Error; err != nil {
+		slog.ErrorContext(ctx, "Failed to count user answers", "user_id", userID, "error", err)
+		return nil, translateError(err)
+	}
+
+	result := base.Order("created_at DESC, id DESC").
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		Find(&page.Items)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "Failed to get user answers", "user_id", userID, "error", result.Error)
+		return nil, translateError(result.Error)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VoteAnswer records the user's vote on an answer, replacing any previous
+// vote, and keeps answers.score in sync in the same transaction.
+func (r *Repository) VoteAnswer(ctx context.Context, answerID uint, userID string, value int, ifMatch []uint) (*models.Answer, error) {
+	var answer models.Answer
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&answer, answerID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(ifMatch, answer.Version); err != nil {
+			return err
+		}
+		if err := checkNotLocked(tx, answer.QuestionID); err != nil {
+			return err
+		}
+
+		var previous models.AnswerVote
+		err := tx.Where("answer_id = ? AND user_id = ?", answerID, userID).First(&previous).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		delta := value - previous.Value
+		if delta == 0 {
+			return nil
+		}
+
+		vote := models.AnswerVote{AnswerID: answerID, UserID: userID, Value: value}
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "answer_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "created_at"}),
+		}).Create(&vote).Error
+		if err != nil {
+			return err
+		}
+
+		var before interface{}
+		if previous.Value != 0 {
+			before = previous
+		}
+		if err := recordAudit(tx, "answer.vote", "answer_vote", voteEntityID(vote), before, vote); err != nil {
+			return err
+		}
+
+		return applyScoreDelta(tx, &answer, delta)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to vote on answer", "answer_id", answerID, "user_id", userID, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &answer, nil
+}
+
+// RetractVote removes the user's vote on an answer. It returns ErrNotFound if
+// the answer does not exist or the user has not voted on it.
+func (r *Repository) RetractVote(ctx context.Context, answerID uint, userID string, ifMatch []uint) (*models.Answer, error) {
+	var answer models.Answer
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&answer, answerID).Error; err != nil {
+			return err
+		}
+		if err := checkVersion(ifMatch, answer.Version); err != nil {
+			return err
+		}
+		if err := checkNotLocked(tx, answer.QuestionID); err != nil {
+			return err
+		}
+
+		var vote models.AnswerVote
+		result := tx.Clauses(clause.Returning{}).
+			Where("answer_id = ? AND user_id = ?", answerID, userID).
+			Delete(&vote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := recordAudit(tx, "answer.unvote", "answer_vote", voteEntityID(vote), vote, nil); err != nil {
+			return err
+		}
+
+		return applyScoreDelta(tx, &answer, -vote.Value)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to retract vote", "answer_id", answerID, "user_id", userID, "error", err)
+		return nil, translateError(err)
+	}
+
+	return &answer, nil
+}
+
+// voteEntityID identifies a vote in the audit log by its composite key.
+func voteEntityID(vote models.AnswerVote) string {
+	return fmt.Sprintf("%d:%s", vote.AnswerID, vote.UserID)
+}
+
+// applyScoreDelta updates the denormalised score. The score is part of both
+// the answer and the question representation, so both versions are bumped.
+func applyScoreDelta(tx *gorm.DB, answer *models.Answer, delta int) error {
+	err := tx.Model(answer).Updates(map[string]interface{}{
+		"score":   gorm.Expr("score + ?", delta),
+		"version": gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	answer.Score += delta
+	answer.Version++
+
+	return touchQuestion(tx, answer.QuestionID)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"slices"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// checkVersion enforces an If-Match precondition. A nil ifMatch means the
+// write is unconditional; an empty one matches no version.
+func checkVersion(ifMatch []uint, current uint) error {
+	if ifMatch == nil || slices.Contains(ifMatch, current) {
+		return nil
+	}
+	return ErrVersionMismatch
+}
+
+// checkNotLocked returns ErrQuestionLocked if the question is locked. The
+// share lock makes a concurrent status change wait for the write.
+func checkNotLocked(tx *gorm.DB, questionID uint) error {
+	var question models.Question
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Select("id", "status").
+		First(&question, questionID).Error
+	if err != nil {
+		return err
+	}
+	if question.Locked() {
+		return ErrQuestionLocked
+	}
+	return nil
+}
+
+// touchQuestion bumps the question versions. Answers are part of the
+// question representation, so any change to them invalidates the question
+// ETag too.
+func touchQuestion(tx *gorm.DB, questionIDs ...uint) error {
+	if len(questionIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&models.Question{}).
+		Where("id IN ?", questionIDs).
+		Update("version", gorm.Expr("version + 1")).Error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersion(t *testing.T) {
+	assert.NoError(t, checkVersion(nil, 3))
+	assert.NoError(t, checkVersion([]uint{2, 3}, 3))
+	assert.ErrorIs(t, checkVersion([]uint{2}, 3), ErrVersionMismatch)
+	assert.ErrorIs(t, checkVersion([]uint{}, 3), ErrVersionMismatch)
+}
@@ -0,0 +1,69 @@
+// Package requestid assigns every request an ID that ties together its log
+// lines, audit events, response headers and error bodies.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header = "X-Request-ID"
+
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored by Middleware, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Middleware accepts the client's X-Request-ID if it is well-formed and
+// generates one otherwise. The ID is stored in the request context and echoed
+// in the response header. It should be the first middleware, so even
+// recovered panics are reported with the ID.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = generate()
+		}
+
+		c.Request = c.Request.WithContext(WithID(c.Request.Context(), id))
+		c.Header(Header, id)
+		c.Next()
+	}
+}
+
+// valid allows IDs from other systems (UUIDs, trace IDs, ...) but nothing that
+// could break log lines or headers.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func generate() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
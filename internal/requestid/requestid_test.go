@@ -0,0 +1,56 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, FromContext(c.Request.Context()))
+	})
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	router := setupRouter()
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"accepts client ID", "req-42", true},
+		{"accepts UUID", "3fa85f64-5717-4562-b3fc-2c963f66afa6", true},
+		{"generates when missing", "", false},
+		{"replaces unsafe ID", "bad id\r\nX-Injected: 1", false},
+		{"replaces long ID", strings.Repeat("a", maxLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(Header)
+			assert.Equal(t, id, w.Body.String())
+			if tt.keep {
+				assert.Equal(t, tt.header, id)
+			} else {
+				assert.Len(t, id, 32)
+			}
+		})
+	}
+}
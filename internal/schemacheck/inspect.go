@@ -0,0 +1,158 @@
+package schemacheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Catalog describes the tables of a database schema, keyed by table name.
+type Catalog map[string]*Table
+
+type Table struct {
+	Columns     map[string]Column
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+type Column struct {
+	// DataType is the information_schema data_type, e.g. "character varying".
+	DataType string
+	Length   *int
+	Nullable bool
+}
+
+func (c Column) describe() string {
+	if c.Length != nil {
+		return fmt.Sprintf("%s(%d)", c.DataType, *c.Length)
+	}
+	return c.DataType
+}
+
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Partial bool
+}
+
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	// OnDelete is the information_schema delete_rule, e.g. "CASCADE".
+	OnDelete string
+}
+
+func (c Catalog) table(name string) *Table {
+	t, ok := c[name]
+	if !ok {
+		t = &Table{Columns: map[string]Column{}}
+		c[name] = t
+	}
+	return t
+}
+
+const columnsQuery = `
+SELECT table_name, column_name, data_type, character_maximum_length, is_nullable = 'YES' AS nullable
+FROM information_schema.columns
+WHERE table_schema = current_schema()
+ORDER BY table_name, ordinal_position`
+
+// information_schema has no view of indexes, so they come from pg_catalog.
+// Expression columns have no attribute and are left out.
+const indexesQuery = `
+SELECT t.relname AS table_name, i.relname AS index_name, ix.indisunique AS is_unique,
+       ix.indpred IS NOT NULL AS partial,
+       (SELECT string_agg(a.attname, ',' ORDER BY k.ord)
+        FROM unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+        JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum) AS columns
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = current_schema()
+ORDER BY t.relname, i.relname`
+
+const foreignKeysQuery = `
+SELECT kcu.table_name, kcu.constraint_name, kcu.column_name,
+       ref.table_name AS ref_table, ref.column_name AS ref_column, rc.delete_rule
+FROM information_schema.referential_constraints rc
+JOIN information_schema.key_column_usage kcu
+  ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name
+JOIN information_schema.key_column_usage ref
+  ON ref.constraint_schema = rc.unique_constraint_schema AND ref.constraint_name = rc.unique_constraint_name
+ AND ref.ordinal_position = kcu.position_in_unique_constraint
+WHERE rc.constraint_schema = current_schema()
+ORDER BY kcu.table_name, kcu.constraint_name, kcu.ordinal_position`
+
+// Inspect reads the tables of the connection's current schema.
+func Inspect(ctx context.Context, db *gorm.DB) (Catalog, error) {
+	db = db.WithContext(ctx)
+	catalog := Catalog{}
+
+	var columns []struct {
+		TableName              string
+		ColumnName             string
+		DataType               string
+		CharacterMaximumLength *int
+		Nullable               bool
+	}
+	if err := db.Raw(columnsQuery).Scan(&columns).Error; err != nil {
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+	for _, c := range columns {
+		catalog.table(c.TableName).Columns[c.ColumnName] = Column{
+			DataType: c.DataType,
+			Length:   c.CharacterMaximumLength,
+			Nullable: c.Nullable,
+		}
+	}
+
+	var indexes []struct {
+		TableName string
+		IndexName string
+		IsUnique  bool
+		Partial   bool
+		Columns   *string
+	}
+	if err := db.Raw(indexesQuery).Scan(&indexes).Error; err != nil {
+		return nil, fmt.Errorf("read indexes: %w", err)
+	}
+	for _, i := range indexes {
+		var cols []string
+		if i.Columns != nil {
+			cols = strings.Split(*i.Columns, ",")
+		}
+		t := catalog.table(i.TableName)
+		t.Indexes = append(t.Indexes, Index{Name: i.IndexName, Columns: cols, Unique: i.IsUnique, Partial: i.Partial})
+	}
+
+	var keys []struct {
+		TableName      string
+		ConstraintName string
+		ColumnName     string
+		RefTable       string
+		RefColumn      string
+		DeleteRule     string
+	}
+	if err := db.Raw(foreignKeysQuery).Scan(&keys).Error; err != nil {
+		return nil, fmt.Errorf("read foreign keys: %w", err)
+	}
+	for _, k := range keys {
+		t := catalog.table(k.TableName)
+		n := len(t.ForeignKeys)
+		if n == 0 || t.ForeignKeys[n-1].Name != k.ConstraintName {
+			t.ForeignKeys = append(t.ForeignKeys, ForeignKey{Name: k.ConstraintName, RefTable: k.RefTable, OnDelete: k.DeleteRule})
+			n++
+		}
+		fk := &t.ForeignKeys[n-1]
+		fk.Columns = append(fk.Columns, k.ColumnName)
+		fk.RefColumns = append(fk.RefColumns, k.RefColumn)
+	}
+
+	return catalog, nil
+}
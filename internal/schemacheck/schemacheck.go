@@ -0,0 +1,304 @@
+// Package schemacheck detects drift between the GORM models and the schema
+// the migrations actually built. The models' tags (not null, index,
+// constraint) are only documentation here, since tables are created by
+// hand-written migrations, so nothing else notices when the two diverge.
+package schemacheck
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type Kind string
+
+const (
+	MissingTable      Kind = "missing_table"
+	MissingColumn     Kind = "missing_column"
+	ColumnType        Kind = "column_type"
+	ColumnNullability Kind = "column_nullability"
+	MissingIndex      Kind = "missing_index"
+	MissingForeignKey Kind = "missing_foreign_key"
+	ForeignKeyAction  Kind = "foreign_key_on_delete"
+)
+
+// Issue is one difference between a model and its table.
+type Issue struct {
+	Table    string `json:"table"`
+	Kind     Kind   `json:"kind"`
+	Object   string `json:"object,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func (i Issue) String() string {
+	s := i.Table
+	if i.Object != "" {
+		s += "." + i.Object
+	}
+	s += ": " + strings.ReplaceAll(string(i.Kind), "_", " ")
+	if i.Expected != "" || i.Actual != "" {
+		s += fmt.Sprintf(" (model %s, database %s)", i.Expected, i.Actual)
+	}
+	return s
+}
+
+// Check parses models with db's naming strategy and compares them with the
+// tables in the current schema.
+func Check(ctx context.Context, db *gorm.DB, models ...any) ([]Issue, error) {
+	schemas, err := Parse(db.NamingStrategy, models...)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := Inspect(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(schemas, catalog), nil
+}
+
+// Parse returns the GORM schemas of models, followed by the join tables of
+// their many2many fields.
+func Parse(namer schema.Namer, models ...any) ([]*schema.Schema, error) {
+	cache := &sync.Map{}
+	seen := map[string]bool{}
+	var schemas []*schema.Schema
+
+	add := func(s *schema.Schema) {
+		if !seen[s.Table] {
+			seen[s.Table] = true
+			schemas = append(schemas, s)
+		}
+	}
+
+	for _, model := range models {
+		s, err := schema.Parse(model, cache, namer)
+		if err != nil {
+			return nil, fmt.Errorf("parse %T: %w", model, err)
+		}
+		add(s)
+	}
+	for _, s := range slices.Clone(schemas) {
+		for _, rel := range s.Relationships.Relations {
+			if rel.JoinTable != nil && !rel.Field.IgnoreMigration {
+				add(rel.JoinTable)
+			}
+		}
+	}
+	return schemas, nil
+}
+
+// Compare reports the columns, indexes and foreign keys declared by schemas
+// that catalog lacks or defines differently. Objects that exist only in the
+// database, such as generated search columns, are not reported.
+func Compare(schemas []*schema.Schema, catalog Catalog) []Issue {
+	var issues []Issue
+	for _, s := range schemas {
+		table, ok := catalog[s.Table]
+		if !ok {
+			issues = append(issues, Issue{Table: s.Table, Kind: MissingTable})
+			continue
+		}
+		issues = append(issues, compareColumns(s, table)...)
+		issues = append(issues, compareIndexes(s, table)...)
+	}
+	issues = append(issues, compareForeignKeys(schemas, catalog)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Table != issues[j].Table {
+			return issues[i].Table < issues[j].Table
+		}
+		return issues[i].Object < issues[j].Object
+	})
+	return issues
+}
+
+func compareColumns(s *schema.Schema, table *Table) []Issue {
+	var issues []Issue
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		col, ok := table.Columns[field.DBName]
+		if !ok {
+			issues = append(issues, Issue{Table: s.Table, Kind: MissingColumn, Object: field.DBName})
+			continue
+		}
+
+		if want := expectedType(field); !typeMatches(field, col) {
+			issues = append(issues, Issue{
+				Table: s.Table, Kind: ColumnType, Object: field.DBName,
+				Expected: want, Actual: col.describe(),
+			})
+		}
+
+		notNull := field.NotNull || field.PrimaryKey
+		switch {
+		case notNull && col.Nullable:
+			issues = append(issues, Issue{
+				Table: s.Table, Kind: ColumnNullability, Object: field.DBName,
+				Expected: "NOT NULL", Actual: "NULL",
+			})
+		case nullable(field) && !col.Nullable:
+			issues = append(issues, Issue{
+				Table: s.Table, Kind: ColumnNullability, Object: field.DBName,
+				Expected: "NULL", Actual: "NOT NULL",
+			})
+		}
+	}
+	return issues
+}
+
+// columnTypes maps GORM's generic data types to the information_schema
+// data_type values that can hold them.
+var columnTypes = map[schema.DataType][]string{
+	schema.Bool:   {"boolean"},
+	schema.Int:    {"smallint", "integer", "bigint"},
+	schema.Uint:   {"smallint", "integer", "bigint"},
+	schema.Float:  {"real", "double precision", "numeric"},
+	schema.String: {"character varying", "character", "text"},
+	schema.Time:   {"timestamp with time zone", "timestamp without time zone", "date"},
+	schema.Bytes:  {"bytea"},
+}
+
+func typeMatches(field *schema.Field, col Column) bool {
+	accepted, ok := columnTypes[field.DataType]
+	if !ok {
+		// An explicit type:... tag, compared without its modifiers.
+		return col.DataType == expectedType(field)
+	}
+	if !slices.Contains(accepted, col.DataType) {
+		return false
+	}
+	if field.DataType == schema.String && field.Size > 0 {
+		return col.Length != nil && *col.Length == field.Size
+	}
+	return true
+}
+
+func expectedType(field *schema.Field) string {
+	if _, ok := columnTypes[field.DataType]; !ok {
+		name, _, _ := strings.Cut(strings.ToLower(string(field.DataType)), "(")
+		return strings.TrimSpace(name)
+	}
+	if field.DataType == schema.String && field.Size > 0 {
+		return fmt.Sprintf("string(%d)", field.Size)
+	}
+	return string(field.DataType)
+}
+
+// nullable reports whether the Go field can hold NULL, so the column must
+// accept it.
+func nullable(field *schema.Field) bool {
+	if field.NotNull || field.PrimaryKey {
+		return false
+	}
+	return field.IndirectFieldType != field.FieldType || field.FieldType.Name() == "DeletedAt"
+}
+
+func compareIndexes(s *schema.Schema, table *Table) []Issue {
+	var issues []Issue
+	for _, idx := range s.ParseIndexes() {
+		columns := make([]string, 0, len(idx.Fields))
+		for _, f := range idx.Fields {
+			if f.Field != nil {
+				columns = append(columns, f.DBName)
+			}
+		}
+		unique := idx.Class == "UNIQUE"
+
+		found := slices.ContainsFunc(table.Indexes, func(got Index) bool {
+			return slices.Equal(got.Columns, columns) && (got.Unique || !unique) && (got.Partial == (idx.Where != ""))
+		})
+		if !found {
+			want := "(" + strings.Join(columns, ", ") + ")"
+			if unique {
+				want = "UNIQUE " + want
+			}
+			issues = append(issues, Issue{Table: s.Table, Kind: MissingIndex, Object: idx.Name, Expected: want, Actual: "none"})
+		}
+	}
+	return issues
+}
+
+func compareForeignKeys(schemas []*schema.Schema, catalog Catalog) []Issue {
+	var issues []Issue
+	seen := map[string]bool{}
+
+	for _, s := range schemas {
+		for _, rel := range sortedRelations(s) {
+			if rel.Field.IgnoreMigration {
+				continue
+			}
+			c := rel.ParseConstraint()
+			if c == nil || c.Schema == nil || c.ReferenceSchema == nil || len(c.ForeignKeys) == 0 {
+				continue
+			}
+
+			columns := dbNames(c.ForeignKeys)
+			references := dbNames(c.References)
+			key := c.Schema.Table + "(" + strings.Join(columns, ",") + ")->" + c.ReferenceSchema.Table
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			table, ok := catalog[c.Schema.Table]
+			if !ok {
+				// Already reported as a missing table.
+				continue
+			}
+			want := fmt.Sprintf("(%s) REFERENCES %s(%s)", strings.Join(columns, ", "), c.ReferenceSchema.Table, strings.Join(references, ", "))
+
+			i := slices.IndexFunc(table.ForeignKeys, func(fk ForeignKey) bool {
+				return fk.RefTable == c.ReferenceSchema.Table && slices.Equal(fk.Columns, columns) && slices.Equal(fk.RefColumns, references)
+			})
+			if i < 0 {
+				issues = append(issues, Issue{Table: c.Schema.Table, Kind: MissingForeignKey, Object: c.Name, Expected: want, Actual: "none"})
+				continue
+			}
+			fk := table.ForeignKeys[i]
+			if c.OnDelete != "" && !strings.EqualFold(c.OnDelete, fk.OnDelete) {
+				issues = append(issues, Issue{
+					Table: c.Schema.Table, Kind: ForeignKeyAction, Object: fk.Name,
+					Expected: "ON DELETE " + strings.ToUpper(c.OnDelete), Actual: "ON DELETE " + fk.OnDelete,
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// sortedRelations returns the relationships of s, including those of its
+// join tables, in a stable order.
+func sortedRelations(s *schema.Schema) []*schema.Relationship {
+	names := make([]string, 0, len(s.Relationships.Relations))
+	for name := range s.Relationships.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rels := make([]*schema.Relationship, 0, len(names))
+	for _, name := range names {
+		rel := s.Relationships.Relations[name]
+		rels = append(rels, rel)
+		if rel.JoinTable != nil {
+			rels = append(rels, sortedRelations(rel.JoinTable)...)
+		}
+	}
+	return rels
+}
+
+func dbNames(fields []*schema.Field) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.DBName
+	}
+	return names
+}
@@ -0,0 +1,141 @@
+package schemacheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NKV510/question-answer-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type Author struct {
+	ID     uint   `gorm:"primaryKey"`
+	Handle string `gorm:"size:32;not null;uniqueIndex"`
+	Posts  []Post `gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE"`
+}
+
+type Post struct {
+	ID        uint   `gorm:"primaryKey"`
+	AuthorID  uint   `gorm:"not null;index"`
+	Title     string `gorm:"not null"`
+	Subtitle  *string
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Views     int64          `gorm:"->;-:migration"`
+}
+
+func length(n int) *int { return &n }
+
+// matchingCatalog is what migrations for Author and Post should produce.
+func matchingCatalog() Catalog {
+	return Catalog{
+		"authors": {
+			Columns: map[string]Column{
+				"id":     {DataType: "integer"},
+				"handle": {DataType: "character varying", Length: length(32)},
+			},
+			Indexes: []Index{
+				{Name: "authors_pkey", Columns: []string{"id"}, Unique: true},
+				{Name: "idx_authors_handle", Columns: []string{"handle"}, Unique: true},
+			},
+		},
+		"posts": {
+			Columns: map[string]Column{
+				"id":         {DataType: "integer"},
+				"author_id":  {DataType: "integer"},
+				"title":      {DataType: "text"},
+				"subtitle":   {DataType: "text", Nullable: true},
+				"created_at": {DataType: "timestamp with time zone", Nullable: true},
+				"deleted_at": {DataType: "timestamp with time zone", Nullable: true},
+				"search":     {DataType: "tsvector", Nullable: true},
+			},
+			Indexes: []Index{
+				{Name: "posts_pkey", Columns: []string{"id"}, Unique: true},
+				{Name: "idx_posts_author_id", Columns: []string{"author_id"}},
+				{Name: "idx_posts_deleted_at", Columns: []string{"deleted_at"}},
+			},
+			ForeignKeys: []ForeignKey{
+				{Name: "posts_author_id_fkey", Columns: []string{"author_id"}, RefTable: "authors", RefColumns: []string{"id"}, OnDelete: "CASCADE"},
+			},
+		},
+	}
+}
+
+func parseTest(t *testing.T) []*schema.Schema {
+	t.Helper()
+	schemas, err := Parse(schema.NamingStrategy{}, &Author{}, &Post{})
+	require.NoError(t, err)
+	return schemas
+}
+
+func TestCompare_NoDrift(t *testing.T) {
+	assert.Empty(t, Compare(parseTest(t), matchingCatalog()))
+}
+
+func TestCompare_Drift(t *testing.T) {
+	catalog := matchingCatalog()
+	posts := catalog["posts"]
+	delete(posts.Columns, "title")
+	posts.Columns["author_id"] = Column{DataType: "text", Nullable: true}
+	posts.Columns["subtitle"] = Column{DataType: "text"}
+	posts.Indexes = posts.Indexes[:2]
+	posts.ForeignKeys[0].OnDelete = "NO ACTION"
+	catalog["authors"].Columns["handle"] = Column{DataType: "character varying", Length: length(255)}
+
+	issues := Compare(parseTest(t), catalog)
+
+	assert.Equal(t, []Issue{
+		{Table: "authors", Kind: ColumnType, Object: "handle", Expected: "string(32)", Actual: "character varying(255)"},
+		{Table: "posts", Kind: ColumnType, Object: "author_id", Expected: "uint", Actual: "text"},
+		{Table: "posts", Kind: ColumnNullability, Object: "author_id", Expected: "NOT NULL", Actual: "NULL"},
+		{Table: "posts", Kind: MissingIndex, Object: "idx_posts_deleted_at", Expected: "(deleted_at)", Actual: "none"},
+		{Table: "posts", Kind: ForeignKeyAction, Object: "posts_author_id_fkey", Expected: "ON DELETE CASCADE", Actual: "ON DELETE NO ACTION"},
+		{Table: "posts", Kind: ColumnNullability, Object: "subtitle", Expected: "NULL", Actual: "NOT NULL"},
+		{Table: "posts", Kind: MissingColumn, Object: "title"},
+	}, issues)
+}
+
+func TestCompare_MissingTableAndForeignKey(t *testing.T) {
+	catalog := matchingCatalog()
+	catalog["posts"].ForeignKeys = nil
+	delete(catalog, "authors")
+
+	issues := Compare(parseTest(t), catalog)
+
+	assert.Equal(t, []Issue{
+		{Table: "authors", Kind: MissingTable},
+		{Table: "posts", Kind: MissingForeignKey, Object: "fk_authors_posts", Expected: "(author_id) REFERENCES authors(id)", Actual: "none"},
+	}, issues)
+}
+
+func TestCompare_UniqueIndexNeedsUniqueness(t *testing.T) {
+	catalog := matchingCatalog()
+	catalog["authors"].Indexes[1].Unique = false
+
+	issues := Compare(parseTest(t), catalog)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, MissingIndex, issues[0].Kind)
+	assert.Equal(t, "UNIQUE (handle)", issues[0].Expected)
+}
+
+func TestParse_Models(t *testing.T) {
+	schemas, err := Parse(schema.NamingStrategy{}, models.All()...)
+	require.NoError(t, err)
+
+	var tables []string
+	for _, s := range schemas {
+		tables = append(tables, s.Table)
+	}
+	assert.Contains(t, tables, "questions")
+	assert.Contains(t, tables, "question_tags")
+}
+
+func TestIssueString(t *testing.T) {
+	assert.Equal(t, "posts.title: missing column", Issue{Table: "posts", Kind: MissingColumn, Object: "title"}.String())
+	assert.Equal(t, "posts.author_id: column nullability (model NOT NULL, database NULL)",
+		Issue{Table: "posts", Kind: ColumnNullability, Object: "author_id", Expected: "NOT NULL", Actual: "NULL"}.String())
+}
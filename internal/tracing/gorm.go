@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin records a client span for every GORM operation as a child of the
+// span in the statement context. Install it with db.Use(tracing.GormPlugin{}).
+// Queries are recorded without their bound values.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, p := range processors {
+		if err := p.before("tracing:before_"+p.operation, startSpan(p.operation)); err != nil {
+			return err
+		}
+		if err := p.after("tracing:after_"+p.operation, endSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		_, span := tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", "postgresql"),
+				attribute.String("db.operation.name", operation),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if db.Statement.Table != "" {
+		span.SetAttributes(attribute.String("db.collection.name", db.Statement.Table))
+	}
+	span.SetAttributes(
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.response.returned_rows", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request, continuing the trace
+// from an incoming traceparent header. The span is named after the route
+// template, and the request context carries it to handlers and queries.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		ctx, span := tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("http.route", route),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+	}
+}
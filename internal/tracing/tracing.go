@@ -0,0 +1,98 @@
+// Package tracing sets up OpenTelemetry tracing: the exporter, a server span
+// per HTTP request and a child span per GORM call.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ServiceName = "question-answer-api"
+
+	instrumentationName = "github.com/NKV510/question-answer-api/internal/tracing"
+)
+
+// Exporter names accepted in OTEL_TRACES_EXPORTER.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The OTLP exporter reads its endpoint and headers from the
+// standard OTEL_EXPORTER_OTLP_* variables; file writes JSON spans to path.
+// With ExporterNone spans are still created, so trace IDs reach the logs and
+// incoming traceparent headers are honoured, but nothing is exported. The
+// returned function flushes pending spans.
+func Setup(ctx context.Context, exporter, path string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var (
+		spanExporter sdktrace.SpanExporter
+		closer       io.Closer
+	)
+	switch exporter {
+	case ExporterNone:
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		var f *os.File
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open trace file: %w", err)
+		}
+		closer = f
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if spanExporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(spanExporter))
+	}
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
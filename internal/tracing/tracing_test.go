@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupRouter(t *testing.T) (*gin.Engine, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(t.Context()) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/questions/:id", func(c *gin.Context) {
+		sc := trace.SpanContextFromContext(c.Request.Context())
+		c.String(http.StatusOK, sc.TraceID().String())
+	})
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	return router, recorder
+}
+
+func TestMiddleware_ServerSpan(t *testing.T) {
+	router, recorder := setupRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/42", nil)
+	router.ServeHTTP(w, req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /questions/:id", spans[0].Name())
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	assert.Equal(t, spans[0].SpanContext().TraceID().String(), w.Body.String())
+}
+
+func TestMiddleware_ContinuesTraceparent(t *testing.T) {
+	router, recorder := setupRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/questions/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Body.String())
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+}
+
+func TestMiddleware_ServerError(t *testing.T) {
+	router, recorder := setupRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/fail", nil)
+	router.ServeHTTP(w, req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The russian configuration stems Cyrillic words; the english one is
+-- concatenated so English text gets its own stop words and stemming.
+ALTER TABLE questions ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('russian', text) || to_tsvector('english', text)) STORED;
+
+ALTER TABLE answers ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('russian', text) || to_tsvector('english', text)) STORED;
+
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
+CREATE INDEX idx_answers_search_vector ON answers USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_answers_search_vector;
+DROP INDEX idx_questions_search_vector;
+ALTER TABLE answers DROP COLUMN search_vector;
+ALTER TABLE questions DROP COLUMN search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE question_revisions (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    editor VARCHAR(255) NOT NULL,
+    previous_text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE answer_revisions (
+    id SERIAL PRIMARY KEY,
+    answer_id INTEGER NOT NULL REFERENCES answers(id) ON DELETE CASCADE,
+    editor VARCHAR(255) NOT NULL,
+    previous_text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_question_revisions_question_id ON question_revisions(question_id);
+CREATE INDEX idx_answer_revisions_answer_id ON answer_revisions(answer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE answer_revisions;
+DROP TABLE question_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE questions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE answers ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE answers DROP COLUMN version;
+ALTER TABLE questions DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id VARCHAR(255) PRIMARY KEY,
+    handle VARCHAR(255) NOT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_users_handle ON users(handle);
+
+-- Every author seen so far becomes a user whose handle and display name
+-- default to the old free-form user_id.
+INSERT INTO users (id, handle, display_name, created_at)
+SELECT user_id, user_id, user_id, MIN(created_at)
+FROM answers
+GROUP BY user_id;
+
+ALTER TABLE answers
+    ADD CONSTRAINT fk_answers_user FOREIGN KEY (user_id) REFERENCES users(id);
+
+ALTER TABLE questions ADD COLUMN author_id VARCHAR(255) REFERENCES users(id);
+
+CREATE INDEX idx_questions_author_id ON questions(author_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_questions_author_id;
+ALTER TABLE questions DROP COLUMN author_id;
+ALTER TABLE answers DROP CONSTRAINT fk_answers_user;
+DROP TABLE users;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE answers ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE answer_votes (
+    answer_id INTEGER NOT NULL REFERENCES answers(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id),
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (answer_id, user_id)
+);
+
+CREATE INDEX idx_answer_votes_user_id ON answer_votes(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE answer_votes;
+ALTER TABLE answers DROP COLUMN score;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE questions
+    ADD COLUMN accepted_answer_id INTEGER REFERENCES answers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_questions_accepted_answer_id ON questions(accepted_answer_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_questions_accepted_answer_id;
+ALTER TABLE questions DROP COLUMN accepted_answer_id;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tags_name ON tags(name);
+
+CREATE TABLE question_tags (
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (question_id, tag_id)
+);
+
+CREATE INDEX idx_question_tags_tag_id ON question_tags(tag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE question_tags;
+DROP TABLE tags;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE,
+    answer_id INTEGER REFERENCES answers(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id),
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT comments_single_target CHECK ((question_id IS NULL) <> (answer_id IS NULL))
+);
+
+CREATE INDEX idx_comments_question_id ON comments(question_id);
+CREATE INDEX idx_comments_answer_id ON comments(answer_id);
+CREATE INDEX idx_comments_user_id ON comments(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE comments;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE questions ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE answers ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_questions_deleted_at ON questions(deleted_at);
+CREATE INDEX idx_answers_deleted_at ON answers(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM answers WHERE deleted_at IS NOT NULL;
+DELETE FROM questions WHERE deleted_at IS NOT NULL;
+
+DROP INDEX idx_answers_deleted_at;
+DROP INDEX idx_questions_deleted_at;
+
+ALTER TABLE answers DROP COLUMN deleted_at;
+ALTER TABLE questions DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE questions
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'open',
+    ADD COLUMN close_reason TEXT,
+    ADD COLUMN duplicate_of INTEGER REFERENCES questions(id) ON DELETE SET NULL,
+    ADD CONSTRAINT questions_status_check CHECK (status IN ('open', 'closed', 'locked', 'duplicate'));
+
+CREATE INDEX idx_questions_status ON questions(status);
+
+CREATE TABLE question_status_changes (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT,
+    duplicate_of INTEGER REFERENCES questions(id) ON DELETE SET NULL,
+    changed_by VARCHAR(255) NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_question_status_changes_question_id ON question_status_changes(question_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE question_status_changes;
+DROP INDEX idx_questions_status;
+
+ALTER TABLE questions
+    DROP CONSTRAINT questions_status_check,
+    DROP COLUMN duplicate_of,
+    DROP COLUMN close_reason,
+    DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user',
+    ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'moderator', 'admin'));
+
+CREATE INDEX idx_users_role ON users(role) WHERE role <> 'user';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_users_role;
+
+ALTER TABLE users
+    DROP CONSTRAINT users_role_check,
+    DROP COLUMN role;
+-- +goose StatementEnd
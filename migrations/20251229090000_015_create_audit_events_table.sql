@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255),
+    action VARCHAR(64) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(128),
+    client_ip VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_actor ON audit_events(actor);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+-- The log is append-only: rows can be inserted but never changed or removed.
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_modify
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_events;
+DROP FUNCTION audit_events_append_only();
+-- +goose StatementEnd